@@ -0,0 +1,117 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gi3d"
+	"github.com/goki/gi/gist"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+	"virtualsoundnw.com/play/gogi3/galaxy"
+)
+
+const (
+	// baseSpacing is how far apart a star's base markers sit, in a row
+	// below it.
+	baseSpacing = 0.0012
+	baseDrop    = 0.003
+	xboatWidth  = 0.0005
+)
+
+// baseMarker is how a kind of base is shown: an icon in the detail panel and
+// a small cube of its color under the star in the scene.
+type baseMarker struct {
+	icon  string
+	color gist.Color
+}
+
+var baseMarkers = map[galaxy.Base]baseMarker{
+	galaxy.NavalBase:    {icon: "star", color: gist.Color{R: 64, G: 128, B: 255, A: 255}},
+	galaxy.ScoutBase:    {icon: "search", color: gist.Color{R: 64, G: 224, B: 64, A: 255}},
+	galaxy.MilitaryBase: {icon: "gear", color: gist.Color{R: 224, G: 64, B: 64, A: 255}},
+	galaxy.WayStation:   {icon: "home", color: gist.Color{R: 0, G: 224, B: 224, A: 255}},
+}
+
+var (
+	// baseModel is the cube drawn for each base.
+	baseModel *gi3d.Box
+	// xboatColor is the X-boat routes' color, drawn over the jump lines.
+	xboatColor  = gist.Color{R: 0, G: 224, B: 224, A: 160}
+	xboatsShown = true
+)
+
+// showBases puts a cube for each of the star's bases in a row under it.
+// Clicking one selects the star.
+func showBases(star *galaxy.Star, sc *gi3d.Scene) {
+	bases := theGalaxy.Bases(star.ID)
+	for id, base := range bases {
+		marker := starGroup.AddNewChild(KiT_StarSolid, fmt.Sprintf("base %s/%d", star.Key(), id)).(*starSolid)
+		marker.star = star
+		marker.SetMeshName(sc, baseModel.Name())
+		marker.Defaults()
+		across := (float32(id) - float32(len(bases)-1)/2) * baseSpacing
+		marker.Pose.Pos.Set(star.X+offsets.x+across, star.Y+offsets.y-baseDrop, star.Z+offsets.z)
+		marker.Mat.Color = baseMarkers[base].color
+	}
+}
+
+// showXBoats draws the X-boat routes between the stars shown, if they are
+// turned on.
+func showXBoats(sc *gi3d.Scene) {
+	if !xboatsShown {
+		return
+	}
+	for _, jump := range theGalaxy.XBoatRoutes() {
+		from, to := theGalaxy.Stars[jump.S1ID], theGalaxy.Stars[jump.S2ID]
+		if !shown(from) || !shown(to) {
+			continue
+		}
+		gi3d.AddNewLine(sc, starGroup, fmt.Sprintf("xboat %s %s", from.Key(), to.Key()),
+			mat32.Vec3{X: from.X + offsets.x, Y: from.Y + offsets.y, Z: from.Z + offsets.z},
+			mat32.Vec3{X: to.X + offsets.x, Y: to.Y + offsets.y, Z: to.Z + offsets.z},
+			xboatWidth, xboatColor)
+	}
+}
+
+// addXBoatControl puts the X-boats check box, showing or hiding the routes,
+// on the toolbar.
+func addXBoatControl(toolBar *gi.ToolBar, sceneView *gi3d.SceneView) {
+	show := gi.AddNewCheckBox(toolBar, "xboats")
+	show.SetText("X-boats")
+	show.SetChecked(xboatsShown)
+	show.ButtonSig.Connect(sceneView.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig != int64(gi.ButtonToggled) {
+			return
+		}
+		xboatsShown = show.IsChecked()
+		sc := sceneView.Scene()
+		updt := sc.UpdateStart()
+		showDetail(sc, detail)
+		sc.Init3D()
+		sc.UpdateEnd(updt)
+	})
+}
+
+// putBaseIcons makes the row in the detail panel that showBaseIcons fills.
+func putBaseIcons(parent *gi.Layout) {
+	workingWorld.bases = gi.AddNewLayout(parent, "bases", gi.LayoutHoriz)
+}
+
+// showBaseIcons lists the star's bases in the panel, each with its icon.
+func showBaseIcons(starID int) {
+	row := workingWorld.bases
+	if row == nil {
+		return
+	}
+	updt := row.UpdateStart()
+	row.DeleteChildren(true)
+	for _, base := range theGalaxy.Bases(starID) {
+		gi.AddNewIcon(row, "icon "+string(base), baseMarkers[base].icon)
+		gi.AddNewLabel(row, "base "+string(base), string(base))
+	}
+	row.UpdateEnd(updt)
+}
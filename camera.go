@@ -0,0 +1,175 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"time"
+
+	"github.com/chewxy/math32"
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gi3d"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+)
+
+const (
+	// frameInterval is how often the camera animation moves the camera.
+	frameInterval = time.Second / 60
+	// orbitPeriod is how long the orbit mode takes to circle the star once.
+	orbitPeriod = 20 * time.Second
+)
+
+// cameraUp keeps the camera's Y axis up as it moves.
+var cameraUp = mat32.Vec3{X: 0, Y: .1, Z: 0}
+
+// cameraFlight is a move of the camera and the point it looks at, eased in
+// and out over its duration.
+type cameraFlight struct {
+	start            time.Time
+	duration         time.Duration
+	fromPos, toPos   mat32.Vec3
+	fromLook, toLook mat32.Vec3
+}
+
+// cameraAnimator flies the camera to each new selection and, in orbit mode,
+// circles it around the star it is looking at. Moving the camera by hand
+// interrupts a flight, as does a new selection, which starts a new flight
+// from wherever the camera has got to. Frames run on the window's event
+// loop, like the scene view's own navigation, so only one of them moves the
+// camera at a time. The frames only tick while there is a flight or an orbit.
+type cameraAnimator struct {
+	ticker *time.Ticker
+	stop   chan struct{}
+	scene  *gi3d.Scene
+	flight *cameraFlight
+	// placed is where the animation last put the camera, to notice it being
+	// moved by hand.
+	placed mat32.Vec3
+	orbit  bool
+	last   time.Time
+}
+
+var camera = &cameraAnimator{}
+
+func (a *cameraAnimator) start(sc *gi3d.Scene) {
+	a.scene = sc
+}
+
+// run starts the frames ticking, if they aren't already.
+func (a *cameraAnimator) run() {
+	if a.ticker != nil {
+		return
+	}
+	a.last = time.Now()
+	a.ticker = time.NewTicker(frameInterval)
+	a.stop = make(chan struct{})
+	tickOnEventLoop(a.ticker.C, a.stop, a.frame)
+}
+
+// idle stops the frames until the next flight or orbit.
+func (a *cameraAnimator) idle() {
+	if a.ticker == nil {
+		return
+	}
+	a.ticker.Stop()
+	close(a.stop)
+	a.ticker = nil
+}
+
+// addOrbitControl puts the orbit mode check box on the toolbar.
+func (a *cameraAnimator) addOrbitControl(toolBar *gi.ToolBar, sceneView *gi3d.SceneView) {
+	orbit := gi.AddNewCheckBox(toolBar, "orbit")
+	orbit.SetText("Orbit")
+	orbit.ButtonSig.Connect(sceneView.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			a.setOrbit(orbit.IsChecked())
+		}
+	})
+}
+
+// flyTo moves the camera to pos looking at look, taking the configured fly
+// time, or at once if that is zero or the animation isn't running.
+func (a *cameraAnimator) flyTo(pos, look mat32.Vec3) {
+	cam := &selection.scene.Camera
+	duration := time.Duration(appConfig.FlySeconds * float32(time.Second))
+	if a.scene == nil || duration <= 0 {
+		a.flight = nil
+		cam.Pose.Pos = pos
+		cam.LookAt(look, cameraUp)
+		a.placed = pos
+		return
+	}
+	a.flight = &cameraFlight{
+		start:    time.Now(),
+		duration: duration,
+		fromPos:  cam.Pose.Pos,
+		toPos:    pos,
+		fromLook: cam.Target,
+		toLook:   look,
+	}
+	a.placed = cam.Pose.Pos
+	a.run()
+}
+
+// setOrbit turns orbit mode on or off.
+func (a *cameraAnimator) setOrbit(on bool) {
+	a.orbit = on
+	if on && a.scene != nil {
+		a.run()
+	}
+}
+
+func (a *cameraAnimator) frame(now time.Time) {
+	elapsed := now.Sub(a.last)
+	a.last = now
+	cam := &a.scene.Camera
+	if a.flight != nil && cam.Pose.Pos != a.placed {
+		a.flight = nil
+	}
+	if a.flight == nil && !a.orbit {
+		a.idle()
+		return
+	}
+
+	updt := a.scene.UpdateStart()
+	if a.flight != nil {
+		progress := float32(now.Sub(a.flight.start)) / float32(a.flight.duration)
+		if progress >= 1 {
+			progress = 1
+		}
+		eased := easeInOut(progress)
+		cam.Pose.Pos = lerp(a.flight.fromPos, a.flight.toPos, eased)
+		cam.LookAt(lerp(a.flight.fromLook, a.flight.toLook, eased), cameraUp)
+		if progress == 1 {
+			a.flight = nil
+		}
+	} else {
+		angle := 2 * math32.Pi * float32(elapsed) / float32(orbitPeriod)
+		sin, cos := math32.Sincos(angle)
+		target := cam.Target
+		offset := cam.Pose.Pos.Sub(target)
+		offset.X, offset.Z = offset.X*cos-offset.Z*sin, offset.X*sin+offset.Z*cos
+		cam.Pose.Pos = target.Add(offset)
+		cam.LookAt(target, cameraUp)
+	}
+	a.placed = cam.Pose.Pos
+	a.scene.UpdateEnd(updt)
+	if a.flight == nil && !a.orbit {
+		a.idle()
+	}
+}
+
+// easeInOut eases a flight's progress, 0 to 1, so it starts and ends slowly.
+func easeInOut(t float32) float32 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	f := 2 - 2*t
+
+	return 1 - f*f*f/2
+}
+
+func lerp(from, to mat32.Vec3, t float32) mat32.Vec3 {
+	return from.Add(to.Sub(from).MulScalar(t))
+}
@@ -0,0 +1,180 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/ki/ki"
+	"virtualsoundnw.com/play/gogi3/galaxy"
+)
+
+// defaultCampaignFile is where the window keeps the referee's edits unless
+// the settings name another file.
+const defaultCampaignFile = "galaxy3d-campaign.json"
+
+// defaultNamesFile is where earlier versions kept the names given to stars.
+const defaultNamesFile = "galaxy3d-names.json"
+
+// campaign holds the referee's edits, read at start up and saved whenever
+// one changes. Each galaxy built has it laid over it.
+var campaign = galaxy.NewCampaign()
+
+// importNames takes the names in a names file from an earlier version into
+// the campaign and saves it, then renames the file with an .imported suffix
+// so they are only taken once. Names the campaign already gives stars win.
+func importNames(path string) error {
+	names, err := loadNames(path)
+	if err != nil || len(names) == 0 {
+		return err
+	}
+	imported := campaign.ImportNames(names)
+	if imported > 0 {
+		err = writeFile(appConfig.Campaign, campaign.WriteJSON)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "galaxy3d: took %d names from %s into %s\n", imported, path, appConfig.Campaign)
+
+	return os.Rename(path, path+".imported")
+}
+
+// campaignEditor is the form under the detail panel that edits the selected
+// star in the campaign.
+type campaignEditor struct {
+	name   *gi.TextField
+	uwp    *gi.TextField
+	note   *gi.TextField
+	zone   *gi.ComboBox
+	status *gi.Label
+}
+
+var editor = &campaignEditor{}
+
+// addControls puts the form in the panel: fields for the name, UWP, note
+// and travel zone, Save and Revert, and buttons adding or cutting a jump
+// from the star marked with "Route from here".
+func (e *campaignEditor) addControls(parent *gi.Layout) {
+	form := gi.AddNewLayout(parent, "campaign", gi.LayoutVert)
+	e.name = e.addField(form, "name", "Name")
+	e.uwp = e.addField(form, "uwp", "UWP")
+	e.note = e.addField(form, "note", "Note")
+	gi.AddNewLabel(form, "zoneLabel", "<b>Zone</b>")
+	zones := make([]string, 0, len(galaxy.Zones))
+	for _, zone := range galaxy.Zones {
+		zones = append(zones, string(zone))
+	}
+	e.zone = gi.AddNewComboBox(form, "zone")
+	e.zone.ItemsFromStringList(zones, false, 0)
+
+	buttons := gi.AddNewLayout(form, "campaignButtons", gi.LayoutHoriz)
+	e.addButton(buttons, "Save", e.save)
+	e.addButton(buttons, "Revert", e.revert)
+	e.addButton(buttons, "Add jump", func() { e.editJump(campaign.AddJump, "Jump added") })
+	e.addButton(buttons, "Cut jump", func() { e.editJump(campaign.RemoveJump, "Jump cut") })
+	e.status = gi.AddNewLabel(form, "campaignStatus", "")
+}
+
+func (e *campaignEditor) addField(form *gi.Layout, name, label string) (field *gi.TextField) {
+	gi.AddNewLabel(form, name+"Label", "<b>"+label+"</b>")
+	field = gi.AddNewTextField(form, name)
+	field.SetStretchMaxWidth()
+
+	return
+}
+
+func (e *campaignEditor) addButton(buttons *gi.Layout, label string, pressed func()) {
+	button := gi.AddNewButton(buttons, label)
+	button.SetText(label)
+	button.ButtonSig.Connect(buttons.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonClicked) {
+			pressed()
+		}
+	})
+}
+
+// show fills the form in for the star.
+func (e *campaignEditor) show(starID int) {
+	if e.name == nil {
+		return
+	}
+	e.name.SetText(theGalaxy.Name(starID))
+	e.uwp.SetText(theGalaxy.World(starID).UWP())
+	e.note.SetText(theGalaxy.Note(starID))
+	for i, zone := range galaxy.Zones {
+		if zone == theGalaxy.World(starID).Zone {
+			e.zone.SetCurIndex(i)
+		}
+	}
+	e.status.SetText("")
+}
+
+// save records the form as the selected star's edit. Values left as
+// generated aren't recorded, so later changes to generation still show.
+func (e *campaignEditor) save() {
+	star := selection.star
+	uwp, err := galaxy.ParseUWP(e.uwp.Text())
+	if err != nil {
+		e.status.SetText(err.Error())
+		return
+	}
+	edit := campaign.Star(star.Key())
+	if edit.World != nil {
+		uwp.GasGiants, uwp.Scout, uwp.Navy, uwp.Military = edit.World.GasGiants, edit.World.Scout, edit.World.Navy,
+			edit.World.Military
+	}
+	// A zone left as shown stays scored from the world, unless it was set.
+	if e.zone.CurIndex >= 0 && e.zone.CurIndex < len(galaxy.Zones) {
+		zone := galaxy.Zones[e.zone.CurIndex]
+		if zone != theGalaxy.World(selection.currentSystem).Zone || edit.World != nil && edit.World.Zone != nil {
+			uwp.Zone = &zone
+		}
+	}
+	edit.World = uwp.Changes(theGalaxy.Unedited(selection.currentSystem))
+	edit.Name = strings.TrimSpace(e.name.Text())
+	if edit.Name == star.Name() {
+		edit.Name = ""
+	}
+	edit.Note = strings.TrimSpace(e.note.Text())
+	campaign.Tidy(star.Key())
+	e.commit("Saved")
+}
+
+// revert drops the selected star's edit.
+func (e *campaignEditor) revert() {
+	delete(campaign.Stars, selection.star.Key())
+	e.commit("Reverted to the generated star")
+}
+
+// editJump adds or cuts the jump between the marked star and the selected
+// one.
+func (e *campaignEditor) editJump(edit func(from, to string), done string) {
+	if planner.from == nil || planner.from.Key() == selection.star.Key() {
+		e.status.SetText("Mark another star with Route from here first")
+		return
+	}
+	edit(planner.from.Key(), selection.star.Key())
+	e.commit(done)
+}
+
+// commit saves the campaign, lays it over the galaxy again and redraws the
+// scene and panel.
+func (e *campaignEditor) commit(done string) {
+	err := writeFile(appConfig.Campaign, campaign.WriteJSON)
+	if err != nil {
+		done = fmt.Sprintf("Saving %s failed: %v", appConfig.Campaign, err)
+	}
+	sc := selection.scene
+	updt := sc.UpdateStart()
+	theGalaxy.SetCampaign(campaign)
+	showDetail(sc, detail)
+	selection.updateWorldLableText(selection.currentSystem)
+	sc.Init3D()
+	sc.UpdateEnd(updt)
+	e.status.SetText(done)
+}
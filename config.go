@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"virtualsoundnw.com/play/gogi3/galaxy"
+)
+
+// config is the galaxy3d settings file, read with -config. For example
+//
+//	{"region": {"from": {"x": -1, "y": 0, "z": 0}, "to": {"x": 2, "y": 1, "z": 0}}, "stream": false,
+//	 "projection": {"axis": "z", "columns": 32, "rows": 40}, "rules": {"density": "galactic"}}
+type config struct {
+	Region galaxy.Region `json:"region"`
+	// Stream moves the region with the camera in the 3D view.
+	Stream bool `json:"stream"`
+	// Projection maps stars to hexes for the SEC export.
+	Projection galaxy.Projection `json:"projection"`
+	// FlySeconds is how long the camera takes to fly to a new selection;
+	// 0 jumps straight there.
+	FlySeconds float32 `json:"flySeconds"`
+	// Load names a galaxy saved as JSON to use instead of generating the
+	// region.
+	Load string `json:"load"`
+	// Rules choose how stars are generated.
+	Rules galaxy.Rules `json:"rules"`
+	// Campaign is the file keeping the referee's edits to the galaxy. The
+	// window keeps them in galaxy3d-campaign.json if it is empty, and
+	// generate applies none.
+	Campaign string `json:"campaign"`
+	// Names is a file of names given to stars before the campaign kept them,
+	// which are taken into the campaign. The window looks for
+	// galaxy3d-names.json if it is empty.
+	Names string `json:"names"`
+}
+
+// appConfig holds the settings galaxy3d was started with.
+var appConfig = defaultConfig()
+
+func defaultConfig() config {
+	return config{Region: galaxy.DefaultRegion, Stream: true, Projection: galaxy.DefaultProjection, FlySeconds: 0.8,
+		Rules: galaxy.DefaultRules}
+}
+
+// loadConfig reads a settings file over the defaults.
+func loadConfig(path string) (cfg config, err error) {
+	cfg = defaultConfig()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(data, &cfg)
+	if err != nil {
+		err = fmt.Errorf("%s: %v", path, err)
+	}
+
+	return
+}
+
+// sectorValue lets a sector be given on the command line as x,y,z.
+type sectorValue galaxy.Sector
+
+func (s *sectorValue) String() string {
+	return fmt.Sprintf("%d,%d,%d", s.X, s.Y, s.Z)
+}
+
+func (s *sectorValue) Set(value string) error {
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 {
+		return fmt.Errorf("sector %q should be x,y,z", value)
+	}
+	coords := make([]int32, 3)
+	for i, part := range parts {
+		coord, err := strconv.ParseInt(strings.TrimSpace(part), 10, 32)
+		if err != nil {
+			return fmt.Errorf("sector %q: %v", value, err)
+		}
+		coords[i] = int32(coord)
+	}
+	s.X, s.Y, s.Z = coords[0], coords[1], coords[2]
+
+	return nil
+}
+
+// configFlags are the settings flags shared by the window and the generate
+// subcommand. Flags given on the command line override the -config file.
+type configFlags struct {
+	flags    *flag.FlagSet
+	path     *string
+	from     sectorValue
+	to       sectorValue
+	stream   *bool
+	axis     *string
+	load     *string
+	fly      *float64
+	classic  *bool
+	density  *string
+	campaign *string
+	names    *string
+}
+
+func addConfigFlags(flags *flag.FlagSet) *configFlags {
+	cf := &configFlags{
+		flags: flags,
+		from:  sectorValue(galaxy.DefaultRegion.From),
+		to:    sectorValue(galaxy.DefaultRegion.To),
+	}
+	cf.path = flags.String("config", "", "JSON settings file")
+	flags.Var(&cf.from, "from", "first corner sector of the region, as x,y,z")
+	flags.Var(&cf.to, "to", "last corner sector of the region, as x,y,z (inclusive)")
+	cf.stream = flags.Bool("stream", true, "move the region with the camera in the 3D view")
+	cf.fly = flags.Float64("fly", float64(defaultConfig().FlySeconds), "seconds the camera takes to fly to a new selection, 0 to jump")
+	cf.load = flags.String("load", "", "galaxy JSON file to show instead of generating the region")
+	cf.classic = flags.Bool("classic", false, "generate main sequence stars only, as the first versions did, so old seeds give the same stars")
+	cf.density = flags.String("density", "uniform", "how many stars each sector holds: "+galaxy.DensityNames())
+	cf.campaign = flags.String("campaign", "", "JSON file keeping the referee's names, world edits, jumps and notes")
+	cf.names = flags.String("names", "", "JSON file of names given to stars by earlier versions, to take into the campaign")
+	cf.axis = flags.String("axis", galaxy.DefaultProjection.Axis, "axis the SEC export projects along: x, y or z")
+
+	return cf
+}
+
+// config returns the settings once the flags have been parsed.
+func (cf *configFlags) config() (cfg config, err error) {
+	cfg = defaultConfig()
+	if *cf.path != "" {
+		cfg, err = loadConfig(*cf.path)
+		if err != nil {
+			return
+		}
+	}
+	cf.flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "from":
+			cfg.Region.From = galaxy.Sector(cf.from)
+		case "to":
+			cfg.Region.To = galaxy.Sector(cf.to)
+		case "stream":
+			cfg.Stream = *cf.stream
+		case "axis":
+			cfg.Projection.Axis = *cf.axis
+		case "load":
+			cfg.Load = *cf.load
+		case "fly":
+			cfg.FlySeconds = float32(*cf.fly)
+		case "classic":
+			cfg.Rules.Classic = *cf.classic
+		case "density":
+			cfg.Rules.Density = *cf.density
+		case "campaign":
+			cfg.Campaign = *cf.campaign
+		case "names":
+			cfg.Names = *cf.names
+		}
+	})
+	err = cfg.Region.Validate()
+	if err == nil {
+		err = cfg.Projection.Validate()
+	}
+	if err == nil {
+		err = cfg.Rules.Validate()
+	}
+
+	return
+}
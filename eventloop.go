@@ -0,0 +1,61 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/oswin"
+	"github.com/goki/ki/ki"
+)
+
+// eventLoopTask is work handed to the window's event loop to run.
+type eventLoopTask func()
+
+// connectEventLoop runs the tasks onEventLoop sends as they arrive. The
+// click handlers, combo boxes and campaign editor all run on the event loop
+// too, so the galaxy and the scene are only ever touched from it.
+func connectEventLoop(win *gi.Window) {
+	win.EventMgr.ConnectEvent(win.Viewport.This(), oswin.CustomEventType, gi.LowRawPri,
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			if event, ok := data.(*oswin.CustomEvent); ok {
+				if task, ok := event.Data.(eventLoopTask); ok {
+					task()
+				}
+			}
+		})
+}
+
+// onEventLoop queues the task to run on the window's event loop.
+func onEventLoop(task func()) {
+	if selection.win != nil {
+		selection.win.SendCustomEvent(eventLoopTask(task))
+	}
+}
+
+// tickOnEventLoop runs the task on the event loop at every tick until stop
+// is closed, skipping ticks while the last one is still waiting so a busy
+// loop doesn't fall behind. A nil stop ticks for good.
+func tickOnEventLoop(ticks <-chan time.Time, stop <-chan struct{}, task func(now time.Time)) {
+	var waiting int32
+	go func() {
+		for {
+			var now time.Time
+			select {
+			case <-stop:
+				return
+			case now = <-ticks:
+			}
+			if !atomic.CompareAndSwapInt32(&waiting, 0, 1) {
+				continue
+			}
+			onEventLoop(func() {
+				atomic.StoreInt32(&waiting, 0)
+				task(now)
+			})
+		}
+	}()
+}
@@ -1,11 +1,20 @@
+//go:build !headless
+// +build !headless
+
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/goki/gi/gi"
 	"github.com/goki/gi/gi3d"
 	"github.com/goki/gi/gimain"
 	"github.com/goki/gi/units"
 	"github.com/goki/ki/ki"
+	"virtualsoundnw.com/play/gogi3/galaxy"
 )
 
 const (
@@ -13,7 +22,45 @@ const (
 	height = 1280
 )
 
-func main() {
+// runWindow opens the 3D window on the galaxy the flags describe.
+func runWindow(args []string) {
+	flags := flag.NewFlagSet("galaxy3d", flag.ExitOnError)
+	settings := addConfigFlags(flags)
+	_ = flags.Parse(args)
+	cfg, err := settings.config()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "galaxy3d:", err)
+		os.Exit(2)
+	}
+	if cfg.Campaign == "" {
+		cfg.Campaign = defaultCampaignFile
+	}
+	if cfg.Names == "" {
+		cfg.Names = defaultNamesFile
+	}
+	appConfig = cfg
+	campaign, err = loadCampaign(cfg.Campaign)
+	if err == nil {
+		err = importNames(cfg.Names)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "galaxy3d:", err)
+		os.Exit(2)
+	}
+	setRegion(cfg.Region)
+	if cfg.Load != "" {
+		loaded, err = loadGalaxy(cfg.Load)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "galaxy3d:", err)
+			os.Exit(2)
+		}
+		// A saved galaxy's own campaign is kept unless the window's has edits.
+		if campaign.Empty() {
+			campaign = loaded.Campaign()
+		}
+		loaded.SetCampaign(campaign)
+		setRegion(loaded.Region())
+	}
 	gimain.Main(func() {
 		mainRun()
 	})
@@ -47,12 +94,17 @@ func mainRun() {
 	renderStars(sc)
 
 	selection.win = win
+	connectEventLoop(win)
 	selection.scene = sc
 	selection.viewPort = vp
+	selection.currentSystem = connectedStar
 	selection.updateWorldLableTextAndCamera(connectedStar)
+	camera.start(sc)
+	streamer.start(sc, appConfig.Stream && loaded == nil)
 	appName := gi.AppName()
 	mainMenu := win.MainMenu
 	mainMenu.ConfigMenus([]string{appName, "File", "Edit", "Window"})
+	addFileMenu(win)
 
 	//	amen := win.MainMenu.ChildByName(appName, 0).(*gi.Action)
 	//	amen.Menu.AddAppMenu(win)
@@ -121,43 +173,65 @@ func addScene(info ki.Ki) (result *gi3d.Scene) {
 	gi.AddNewLabel(selection.toolBar, "select", "Select:")
 	selection.toolBar.AddAction(gi.ActOpts{Icon: "wedge-left"}, sceneView.This(),
 		func(recv, send ki.Ki, sig int64, data interface{}) {
-			match := -1
-			for sID, next := range selection.choose() {
-				if selection.currentSystem == next.id {
-					match = sID
-					break
-				}
-			}
-			if match > -1 {
-				match--
-				if match < 0 {
-					match = len(selection.choose()) - 1
-				}
-			}
-			selection.currentSystem = selection.choose()[match].id
-			selection.updateWorldLableTextAndCamera(selection.currentSystem)
+			selection.step(-1)
 		})
 	selection.toolBar.AddAction(gi.ActOpts{Icon: "wedge-right"}, sceneView.This(),
 		func(recv, send ki.Ki, sig int64, data interface{}) {
-			match := -1
-			for sID, next := range selection.choose() {
-				if selection.currentSystem == next.id {
-					match = sID
-					break
-				}
-			}
-			if match > -1 {
-				match++
-				if match >= len(selection.choose()) {
-					match = 0
-				}
-			}
-			selection.currentSystem = selection.choose()[match].id
-			selection.updateWorldLableTextAndCamera(selection.currentSystem)
+			selection.step(1)
 		})
+	planner.addControls(selection.toolBar, sceneView)
+	camera.addOrbitControl(selection.toolBar, sceneView)
+	planets.addControl(selection.toolBar, sceneView)
+	addXBoatControl(selection.toolBar, sceneView)
+	addPolityControl(selection.toolBar, sceneView)
+	trade.addControl(selection.toolBar, sceneView)
 	result = sceneView.Scene()
 	result.BgColor.SetUInt8(0, 0, 0, 255)
 	gi3d.AddNewAmbientLight(result, "ambient", 0.6, gi3d.DirectSun)
 
 	return
 }
+
+// Where the File menu exports write the galaxy on screen.
+const (
+	secExportFile  = "galaxy3d.sec"
+	jsonExportFile = "galaxy3d.json"
+)
+
+// maxDroppedListed is how many of the worlds a SEC export left out its
+// dialog names.
+const maxDroppedListed = 10
+
+func addFileMenu(win *gi.Window) {
+	fileMenu := win.MainMenu.ChildByName("File", 0).(*gi.Action)
+	fileMenu.Menu.AddAction(gi.ActOpts{Label: "Export SEC"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			worlds := make([]*galaxy.World, 0)
+			for _, star := range theGalaxy.Stars {
+				worlds = append(worlds, theGalaxy.World(star.ID))
+			}
+			paths, dropped, err := writeSECFiles(theGalaxy, worlds, appConfig.Projection, secExportFile)
+			message := fmt.Sprintf("Wrote %s", strings.Join(paths, ", "))
+			if len(dropped) > 0 {
+				names := droppedStars(theGalaxy, dropped, appConfig.Projection)
+				if len(names) > maxDroppedListed {
+					names = append(names[:maxDroppedListed], "...")
+				}
+				message += fmt.Sprintf(". Left out %d worlds sharing a hex with a more populous one: %s", len(dropped),
+					strings.Join(names, ", "))
+			}
+			if err != nil {
+				message = fmt.Sprintf("Export failed: %v", err)
+			}
+			gi.PromptDialog(win.Viewport, gi.DlgOpts{Title: "Export SEC", Prompt: message}, true, false, nil, nil)
+		})
+	fileMenu.Menu.AddAction(gi.ActOpts{Label: "Export JSON"}, win.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			err := writeFile(jsonExportFile, theGalaxy.WriteJSON)
+			message := fmt.Sprintf("Wrote %s", jsonExportFile)
+			if err != nil {
+				message = fmt.Sprintf("Export failed: %v", err)
+			}
+			gi.PromptDialog(win.Viewport, gi.DlgOpts{Title: "Export JSON", Prompt: message}, true, false, nil, nil)
+		})
+}
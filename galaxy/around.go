@@ -0,0 +1,136 @@
+package galaxy
+
+import (
+	"sort"
+)
+
+// surroundings are the galaxy's stars with those of the sectors around it,
+// which the X-boat network, trade and polities are worked out over so that
+// near the region's edge they agree with what a larger window has. The galaxy's own stars come first,
+// with their IDs, and the others are numbered after them. A galaxy that can't
+// generate its neighbours has only its own stars.
+type surroundings struct {
+	region Region
+	// own is how many of the stars are the galaxy's.
+	own    int
+	stars  []*Star
+	worlds []*World
+	// jumps are the galaxy's jumps and those reaching the sectors around it,
+	// with the campaign's edits.
+	jumps []*Jump
+	// keys are the stars' Keys, which order them the same in every region.
+	keys []string
+}
+
+// inside reports whether the star is one of the galaxy's own.
+func (s *surroundings) inside(id int) bool {
+	return id < s.own
+}
+
+// near reports whether the star lies within the given parsecs of the region.
+func (s *surroundings) near(id int, parsecs float32) bool {
+	star := s.stars[id]
+	gap := func(at float32, from, to int32) float32 {
+		if at < float32(from) {
+			return float32(from) - at
+		}
+		if at > float32(to+1) {
+			return at - float32(to+1)
+		}
+
+		return 0
+	}
+	dx := gap(star.X, s.region.From.X, s.region.To.X)
+	dy := gap(star.Y, s.region.From.Y, s.region.To.Y)
+	dz := gap(star.Z, s.region.From.Z, s.region.To.Z)
+	reach := parsecs / (100 * parsecsPerLightYear)
+
+	return dx*dx+dy*dy+dz*dz <= reach*reach
+}
+
+// neighbours files the jumps that pass the test under both their stars, each
+// star's in the order of the stars they lead to, so searches over them take
+// the same way in every region.
+func (s *surroundings) neighbours(passes func(jump *Jump) bool) (neighbours map[int][]*Jump) {
+	neighbours = make(map[int][]*Jump)
+	for _, jump := range s.jumps {
+		if passes(jump) {
+			neighbours[jump.S1ID] = append(neighbours[jump.S1ID], jump)
+			neighbours[jump.S2ID] = append(neighbours[jump.S2ID], jump)
+		}
+	}
+	for at, jumps := range neighbours {
+		at, jumps := at, jumps
+		sort.Slice(jumps, func(i, j int) bool {
+			return s.keys[jumps[i].Neighbour(at)] < s.keys[jumps[j].Neighbour(at)]
+		})
+	}
+
+	return
+}
+
+func (g *Galaxy) surroundings() *surroundings {
+	if g.around == nil {
+		g.around = g.buildSurroundings()
+	}
+
+	return g.around
+}
+
+// buildSurroundings adds the stars of the sectors around the galaxy, with
+// their worlds and jumps and the campaign's edits to them. The jumps between
+// the galaxy's own stars are its own, and the others are picked as Window
+// picks them, so they are the same whatever region is around.
+func (g *Galaxy) buildSurroundings() (s *surroundings) {
+	s = &surroundings{region: g.Region(), own: len(g.Stars), stars: append([]*Star{}, g.Stars...),
+		worlds: append([]*World{}, g.worlds...), jumps: append([]*Jump{}, g.Jumps...)}
+	if g.sectors != nil && len(g.Stars) > 0 {
+		c := g.Campaign()
+		bySector := make(map[Sector][]*Star)
+		for _, star := range g.Stars {
+			bySector[star.Sector] = append(bySector[star.Sector], star)
+		}
+		context := make([]*Star, 0)
+		for _, sector := range s.region.Grow(1).Sectors() {
+			if s.region.Contains(sector) {
+				for _, star := range bySector[sector] {
+					context = append(context, star)
+				}
+				continue
+			}
+			worlds := g.sectors.Worlds(sector)
+			for index, star := range g.sectors.Stars(sector) {
+				starCopy := *star
+				starCopy.ID = len(s.stars)
+				world := worlds[index]
+				if edit := c.Stars[star.Key()]; edit != nil && edit.World != nil {
+					world = edit.World.apply(world)
+					edit.World.rezone(&starCopy, world)
+				}
+				context = append(context, &starCopy)
+				s.stars = append(s.stars, &starCopy)
+				s.worlds = append(s.worlds, world)
+			}
+		}
+
+		outer := make([]*Jump, 0)
+		for _, jump := range findJumps(context, nil) {
+			if !s.inside(jump.S1ID) || !s.inside(jump.S2ID) {
+				outer = append(outer, jump)
+			}
+		}
+		kept, added := c.editJumps(s.stars, outer)
+		s.jumps = append(s.jumps, kept...)
+		for _, jump := range added {
+			if !s.inside(jump.S1ID) || !s.inside(jump.S2ID) {
+				s.jumps = append(s.jumps, jump)
+			}
+		}
+	}
+	s.keys = make([]string, len(s.stars))
+	for id, star := range s.stars {
+		s.keys[id] = star.Key()
+	}
+
+	return
+}
@@ -0,0 +1,119 @@
+package galaxy
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// Base is an installation at a world's starport.
+type Base string
+
+// The bases a world can have. Way stations are the scouts' X-boat relays,
+// at scout bases on the X-boat network.
+const (
+	NavalBase    Base = "Naval"
+	ScoutBase    Base = "Scout"
+	MilitaryBase Base = "Military"
+	WayStation   Base = "Way station"
+)
+
+// Bases lists the kinds of base in the order they are shown.
+var Bases = []Base{NavalBase, ScoutBase, MilitaryBase, WayStation}
+
+// Code is the base's letter in the Second Survey formats.
+func (b Base) Code() string {
+	switch b {
+	case NavalBase:
+		return "N"
+	case ScoutBase:
+		return "S"
+	case MilitaryBase:
+		return "M"
+	case WayStation:
+		return "W"
+	default:
+		return ""
+	}
+}
+
+// scoutDMs modify the scout base roll by starport. E and X ports never have
+// one.
+var scoutDMs = map[string]int{"A": -3, "B": -2, "C": -1, "D": 0}
+
+// getScout rolls for a scout base: 7 or more on 2D6, harder at the busier
+// ports the scouts leave to the navy.
+func getScout(rand *rand.Rand, starPort string) (scout bool) {
+	roll := twoD6(rand)
+	dm, ok := scoutDMs[starPort]
+	scout = ok && roll+dm > 6
+
+	return
+}
+
+// getNavy rolls for a naval base, which only A and B ports can have: 7 or
+// more on 2D6.
+func getNavy(rand *rand.Rand, starPort string) (navy bool) {
+	if starPort == "A" || starPort == "B" {
+		navy = twoD6(rand) > 6
+	}
+
+	return
+}
+
+// getMilitary rolls for a military base: 10 or more on 2D6, or 9 or more at
+// an A or B port whose world is thinly or densely populated, 6 or more if
+// its air is tainted too.
+func getMilitary(rand *rand.Rand, starPort string, popBase int, tainted bool) (mil bool) {
+	needs := 9
+	if (starPort == "A" || starPort == "B") && (popBase < 4 || popBase > 7) {
+		needs = 8
+		if tainted {
+			needs = 5
+		}
+	}
+	mil = twoD6(rand) > needs
+
+	return
+}
+
+// Bases lists the bases at the star's world, in the order of Bases. A scout
+// base on the X-boat network is a way station as well.
+func (g *Galaxy) Bases(starID int) (bases []Base) {
+	world := g.World(starID)
+	bases = make([]Base, 0)
+	if world.Navy {
+		bases = append(bases, NavalBase)
+	}
+	if world.Scout {
+		bases = append(bases, ScoutBase)
+	}
+	if world.Military {
+		bases = append(bases, MilitaryBase)
+	}
+	if world.Scout && g.OnXBoatNetwork(starID) {
+		bases = append(bases, WayStation)
+	}
+
+	return
+}
+
+// BaseList writes the star's bases as a comma separated list.
+func (g *Galaxy) BaseList(starID int) string {
+	names := make([]string, 0)
+	for _, base := range g.Bases(starID) {
+		names = append(names, string(base))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// HasBase reports whether the star's world has the base.
+func (g *Galaxy) HasBase(starID int, base Base) bool {
+	for _, has := range g.Bases(starID) {
+		if has == base {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,502 @@
+package galaxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// CampaignVersion is the version of the campaign JSON written by WriteJSON.
+const CampaignVersion = 1
+
+// Campaign is a referee's record of a game, layered over the generated
+// galaxy: stars renamed, worlds changed, jumps added or cut and notes. Stars
+// are keyed by Key, so the edits hold in every region and every run, and
+// edits to stars outside a galaxy are kept but have no effect on it.
+type Campaign struct {
+	Version int                  `json:"version"`
+	Stars   map[string]*StarEdit `json:"stars"`
+	// AddJumps are jumps drawn where generation drew none, RemoveJumps
+	// generated jumps taken away.
+	AddJumps    []JumpEdit `json:"addJumps,omitempty"`
+	RemoveJumps []JumpEdit `json:"removeJumps,omitempty"`
+}
+
+// StarEdit is what a campaign changes about one star. Empty values change
+// nothing.
+type StarEdit struct {
+	Name  string     `json:"name,omitempty"`
+	World *WorldEdit `json:"world,omitempty"`
+	Note  string     `json:"note,omitempty"`
+}
+
+// WorldEdit patches a mainworld. Each value set replaces the rolled one; the
+// UWP values are digits, as in the profile, and the descriptions follow them.
+type WorldEdit struct {
+	StarPort   *string `json:"starPort,omitempty"`
+	Size       *int    `json:"size,omitempty"`
+	Atmosphere *int    `json:"atmosphere,omitempty"`
+	Hydro      *int    `json:"hydro,omitempty"`
+	Population *int    `json:"population,omitempty"`
+	Government *int    `json:"government,omitempty"`
+	Law        *int    `json:"law,omitempty"`
+	TechLevel  *int    `json:"techLevel,omitempty"`
+	GasGiants  *int    `json:"gasGiants,omitempty"`
+	Scout      *bool   `json:"scout,omitempty"`
+	Navy       *bool   `json:"navy,omitempty"`
+	Military   *bool   `json:"military,omitempty"`
+	Zone       *Zone   `json:"zone,omitempty"`
+}
+
+// JumpEdit names the stars a jump joins by Key, in either order.
+type JumpEdit struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// starPorts are the starport classes a world can have, best first.
+const starPorts = "ABCDEX"
+
+// NewCampaign returns a campaign with no edits.
+func NewCampaign() *Campaign {
+	return &Campaign{Version: CampaignVersion, Stars: make(map[string]*StarEdit)}
+}
+
+// Empty reports whether the campaign has no edits.
+func (c *Campaign) Empty() bool {
+	return len(c.Stars) == 0 && len(c.AddJumps) == 0 && len(c.RemoveJumps) == 0
+}
+
+// ReadCampaign reads a campaign saved by WriteJSON, or written by hand, and
+// checks its edits.
+func ReadCampaign(r io.Reader) (c *Campaign, err error) {
+	c = NewCampaign()
+	err = json.NewDecoder(r).Decode(c)
+	if err == nil {
+		err = c.check()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// check checks a campaign just read, wherever it was kept.
+func (c *Campaign) check() error {
+	if c.Version < 1 || c.Version > CampaignVersion {
+		return fmt.Errorf("campaign version %d, can read 1 to %d", c.Version, CampaignVersion)
+	}
+	if c.Stars == nil {
+		c.Stars = make(map[string]*StarEdit)
+	}
+
+	return c.Validate()
+}
+
+// WriteJSON saves the campaign as JSON that ReadCampaign reads back.
+func (c *Campaign) WriteJSON(w io.Writer) error {
+	c.Version = CampaignVersion
+	out := json.NewEncoder(w)
+	out.SetIndent("", " ")
+
+	return out.Encode(c)
+}
+
+// Validate reports the first edit naming a star by something other than a
+// Key, or setting a world value that isn't in its table.
+func (c *Campaign) Validate() error {
+	for key, edit := range c.Stars {
+		if !validKey(key) {
+			return fmt.Errorf("campaign star %q should be a key such as 0,-1,2/17", key)
+		}
+		if edit != nil && edit.World != nil {
+			err := edit.World.Validate()
+			if err != nil {
+				return fmt.Errorf("campaign star %s: %v", key, err)
+			}
+		}
+	}
+	for _, jump := range append(append([]JumpEdit{}, c.AddJumps...), c.RemoveJumps...) {
+		if !validKey(jump.From) || !validKey(jump.To) || jump.From == jump.To {
+			return fmt.Errorf("campaign jump from %q to %q doesn't join two stars", jump.From, jump.To)
+		}
+	}
+
+	return nil
+}
+
+// validKey reports whether the string is a star Key.
+func validKey(key string) bool {
+	_, _, ok := parseKey(key)
+
+	return ok
+}
+
+// parseKey splits a star Key into its sector and index.
+func parseKey(key string) (sector Sector, index int, ok bool) {
+	n, err := fmt.Sscanf(key, "%d,%d,%d/%d", &sector.X, &sector.Y, &sector.Z, &index)
+	ok = err == nil && n == 4 && index >= 0 && key == (&Star{Sector: sector, Index: index}).Key()
+
+	return
+}
+
+// Validate reports a value outside the tables worlds are described from.
+func (e *WorldEdit) Validate() error {
+	if e.StarPort != nil && (len(*e.StarPort) != 1 || !strings.Contains(starPorts, *e.StarPort)) {
+		return fmt.Errorf("starport %q should be one of %s", *e.StarPort, starPorts)
+	}
+	if e.Zone != nil && !validZone(*e.Zone) {
+		return fmt.Errorf("zone %q should be Green, Amber or Red", *e.Zone)
+	}
+	limits := []struct {
+		name  string
+		value *int
+		high  int
+	}{
+		{"size", e.Size, 15},
+		{"atmosphere", e.Atmosphere, 15},
+		{"hydro", e.Hydro, 10},
+		{"population", e.Population, 15},
+		{"government", e.Government, len(govByBase) - 1},
+		{"law", e.Law, len(lawLevelByBase) - 1},
+		{"techLevel", e.TechLevel, len(eHexDigits) - 1},
+		{"gasGiants", e.GasGiants, 9},
+	}
+	for _, limit := range limits {
+		if limit.value != nil && (*limit.value < 0 || *limit.value > limit.high) {
+			return fmt.Errorf("%s %d should be 0 to %d", limit.name, *limit.value, limit.high)
+		}
+	}
+
+	return nil
+}
+
+// ParseUWP reads a Universal World Profile, such as A788899-C, into an edit
+// setting each of its values.
+func ParseUWP(uwp string) (edit WorldEdit, err error) {
+	uwp = strings.ToUpper(strings.TrimSpace(uwp))
+	if len(uwp) != 9 || uwp[7] != '-' {
+		return edit, fmt.Errorf("UWP %q should look like A788899-C", uwp)
+	}
+	port := uwp[:1]
+	edit.StarPort = &port
+	digits := []**int{&edit.Size, &edit.Atmosphere, &edit.Hydro, &edit.Population, &edit.Government, &edit.Law}
+	for i, digit := range digits {
+		value := strings.IndexByte(eHexDigits, uwp[i+1])
+		if value < 0 {
+			return edit, fmt.Errorf("UWP %q: %q isn't a UWP digit", uwp, uwp[i+1])
+		}
+		*digit = &value
+	}
+	tech := strings.IndexByte(eHexDigits, uwp[8])
+	if tech < 0 {
+		return edit, fmt.Errorf("UWP %q: %q isn't a UWP digit", uwp, uwp[8])
+	}
+	edit.TechLevel = &tech
+	err = edit.Validate()
+
+	return
+}
+
+// Changes is the edit less the values the world already has, nil if that
+// leaves nothing to change.
+func (e WorldEdit) Changes(world *World) *WorldEdit {
+	if e.StarPort != nil && *e.StarPort == world.StarPort {
+		e.StarPort = nil
+	}
+	if e.Zone != nil && *e.Zone == world.Zone {
+		e.Zone = nil
+	}
+	unchanged := func(value **int, has int) {
+		if *value != nil && **value == has {
+			*value = nil
+		}
+	}
+	unchanged(&e.Size, world.SizeBase)
+	unchanged(&e.Atmosphere, world.AtmosphereBase)
+	unchanged(&e.Hydro, world.HydroBase)
+	unchanged(&e.Population, world.PopBase)
+	unchanged(&e.Government, world.GovernmentBase)
+	unchanged(&e.Law, world.LawBase)
+	unchanged(&e.TechLevel, world.TechLevelBase)
+	unchanged(&e.GasGiants, world.GasGiants)
+	for _, base := range []struct {
+		value **bool
+		has   bool
+	}{{&e.Scout, world.Scout}, {&e.Navy, world.Navy}, {&e.Military, world.Military}} {
+		if *base.value != nil && **base.value == base.has {
+			*base.value = nil
+		}
+	}
+	if e == (WorldEdit{}) {
+		return nil
+	}
+
+	return &e
+}
+
+// apply returns a copy of the world with the edit's values, and the
+// descriptions that go with them.
+func (e *WorldEdit) apply(world *World) *World {
+	edited := *world
+	if e.StarPort != nil {
+		edited.StarPort = *e.StarPort
+	}
+	if e.Size != nil {
+		edited.SizeBase = *e.Size
+		edited.Size = 1600 * edited.SizeBase
+	}
+	if e.Atmosphere != nil {
+		edited.AtmosphereBase = *e.Atmosphere
+		edited.Atmosphere = getAtmosphereFromID(edited.AtmosphereBase)
+	}
+	if e.Hydro != nil {
+		edited.HydroBase = *e.Hydro
+		edited.Hydro = 10 * edited.HydroBase
+	}
+	if e.Population != nil {
+		edited.PopBase = *e.Population
+		edited.Population = scalePopulation(world.Population, world.PopBase, edited.PopBase)
+	}
+	if e.Government != nil {
+		edited.GovernmentBase = *e.Government
+		edited.Government = govByBase[edited.GovernmentBase]
+	}
+	if e.Law != nil {
+		edited.LawBase = *e.Law
+		edited.LawLevel = lawLevelByBase[edited.LawBase]
+	}
+	if e.TechLevel != nil {
+		edited.TechLevelBase = *e.TechLevel
+		edited.TechLevel = techLevelCode(edited.TechLevelBase)
+	}
+	if e.GasGiants != nil {
+		edited.GasGiants = *e.GasGiants
+	}
+	if e.Scout != nil {
+		edited.Scout = *e.Scout
+	}
+	if e.Navy != nil {
+		edited.Navy = *e.Navy
+	}
+	if e.Military != nil {
+		edited.Military = *e.Military
+	}
+	if e.Zone != nil {
+		edited.Zone = *e.Zone
+		edited.ZoneReason = ""
+	}
+
+	return &edited
+}
+
+// rezone scores an edited world's travel zone again when the edit changes
+// what the zone hazards look at and doesn't set the zone itself. The star's
+// dice roll the same event as they did for the generated world.
+func (e *WorldEdit) rezone(star *Star, world *World) {
+	if e.Zone != nil {
+		return
+	}
+	if e.Atmosphere != nil || e.Population != nil || e.Government != nil || e.Law != nil {
+		world.Zone, world.ZoneReason = travelZone(world, starDice(star, zoneSeed))
+	}
+}
+
+// scalePopulation keeps the leading digits of a population when its digit
+// changes, so a world of 3.2 million edited to digit 8 has 320 million.
+func scalePopulation(population uint64, from, to int) uint64 {
+	if to < 1 {
+		return 0
+	}
+	scaled := float64(1)
+	if population > 0 {
+		scaled = float64(population) / math.Pow10(from)
+	}
+
+	return uint64(scaled * math.Pow10(to))
+}
+
+// Star returns the star's edit, adding an empty one if it has none.
+func (c *Campaign) Star(key string) *StarEdit {
+	edit, ok := c.Stars[key]
+	if !ok || edit == nil {
+		edit = &StarEdit{}
+		c.Stars[key] = edit
+	}
+
+	return edit
+}
+
+// ImportNames gives the stars the names, keeping any name the campaign
+// already gives a star and skipping empty names and keys that aren't star
+// Keys. It returns how many names it took.
+func (c *Campaign) ImportNames(names Names) (imported int) {
+	for key, name := range names {
+		if name == "" || !validKey(key) {
+			continue
+		}
+		if edit := c.Star(key); edit.Name == "" {
+			edit.Name = name
+			imported++
+		}
+	}
+
+	return
+}
+
+// Tidy drops the star's edit if it no longer changes anything.
+func (c *Campaign) Tidy(key string) {
+	if edit, ok := c.Stars[key]; ok && (edit == nil || *edit == StarEdit{}) {
+		delete(c.Stars, key)
+	}
+}
+
+// AddJump draws a jump between the stars: a cut jump is restored, and any
+// other is added.
+func (c *Campaign) AddJump(from, to string) {
+	if jumps, cut := withoutJump(c.RemoveJumps, from, to); cut {
+		c.RemoveJumps = jumps
+		return
+	}
+	c.AddJumps = append(c.AddJumps, JumpEdit{From: from, To: to})
+}
+
+// RemoveJump takes away the jump between the stars: an added jump is dropped,
+// and any other is cut.
+func (c *Campaign) RemoveJump(from, to string) {
+	if jumps, added := withoutJump(c.AddJumps, from, to); added {
+		c.AddJumps = jumps
+		return
+	}
+	c.RemoveJumps = append(c.RemoveJumps, JumpEdit{From: from, To: to})
+}
+
+// withoutJump removes the jump between the stars from the list, reporting
+// whether it was there.
+func withoutJump(jumps []JumpEdit, from, to string) (kept []JumpEdit, found bool) {
+	kept = make([]JumpEdit, 0, len(jumps))
+	for _, jump := range jumps {
+		if jump.From == from && jump.To == to || jump.From == to && jump.To == from {
+			found = true
+		} else {
+			kept = append(kept, jump)
+		}
+	}
+
+	return
+}
+
+// SetCampaign lays the campaign's edits over the galaxy as it was generated or
+// loaded. Setting it again, after the campaign changes, starts from that
+// galaxy afresh, so edits taken out of the campaign are undone. A nil
+// campaign has no edits.
+func (g *Galaxy) SetCampaign(c *Campaign) {
+	if c == nil {
+		c = NewCampaign()
+	}
+	if g.uneditedWorlds == nil {
+		g.uneditedWorlds = g.worlds
+		g.uneditedJumps = g.Jumps
+	}
+	g.campaign = c
+	g.worlds = append([]*World{}, g.uneditedWorlds...)
+	for id, star := range g.Stars {
+		if edit := c.Stars[star.Key()]; edit != nil && edit.World != nil {
+			g.worlds[id] = edit.World.apply(g.worlds[id])
+			edit.World.rezone(star, g.worlds[id])
+		}
+	}
+	jumps, added := c.editJumps(g.Stars, g.uneditedJumps)
+	g.setJumps(append(jumps, added...))
+	// The referee's jumps are in the jump lists however long they are.
+	for _, jump := range added {
+		if jump.Distance >= shortJump {
+			g.fileJump(jump)
+		}
+	}
+}
+
+// editJumps cuts the campaign's removed jumps from the jumps between the
+// stars, which are numbered by their place in the list, and draws its added
+// ones that aren't there already.
+func (c *Campaign) editJumps(stars []*Star, jumps []*Jump) (kept, added []*Jump) {
+	ids := make(map[string]int)
+	for id, star := range stars {
+		ids[star.Key()] = id
+	}
+	// resolve finds the stars a jump edit joins, if both are in the list.
+	resolve := func(edit JumpEdit) (pair jumpPair, ok bool) {
+		from, fromOK := ids[edit.From]
+		to, toOK := ids[edit.To]
+		pair = pairOf(&Jump{S1ID: from, S2ID: to})
+
+		return pair, fromOK && toOK
+	}
+	removed := make(map[jumpPair]bool)
+	for _, edit := range c.RemoveJumps {
+		if pair, ok := resolve(edit); ok {
+			removed[pair] = true
+		}
+	}
+	kept = make([]*Jump, 0, len(jumps))
+	drawn := make(map[jumpPair]bool)
+	for _, jump := range jumps {
+		if !removed[pairOf(jump)] {
+			kept = append(kept, jump)
+			drawn[pairOf(jump)] = true
+		}
+	}
+	added = make([]*Jump, 0, len(c.AddJumps))
+	for _, edit := range c.AddJumps {
+		if pair, ok := resolve(edit); ok && !drawn[pair] {
+			added = append(added, addedJump(stars[pair[0]], stars[pair[1]]))
+			drawn[pair] = true
+		}
+	}
+
+	return
+}
+
+// addedJump is a jump a campaign draws, rated like a generated one however
+// long it is.
+func addedJump(from, to *Star) *Jump {
+	length := distance(from, to) * 100 * parsecsPerLightYear
+	parsecs := int(length)
+
+	return &Jump{Color: jumpColor(parsecs), Parsecs: parsecs, Distance: length, S1ID: from.ID, S2ID: to.ID}
+}
+
+// Campaign is the campaign laid over the galaxy; a galaxy without one has an
+// empty one.
+func (g *Galaxy) Campaign() *Campaign {
+	if g.campaign == nil {
+		return NewCampaign()
+	}
+
+	return g.campaign
+}
+
+// Note is the referee's note on the star, if any.
+func (g *Galaxy) Note(starID int) string {
+	key := g.Stars[starID].Key()
+	if g.campaign != nil {
+		if edit := g.campaign.Stars[key]; edit != nil && edit.Note != "" {
+			return edit.Note
+		}
+	}
+
+	return g.notes[key]
+}
+
+// Unedited is the star's world as generated or loaded, before the campaign's
+// edits.
+func (g *Galaxy) Unedited(starID int) *World {
+	if g.uneditedWorlds != nil {
+		return g.uneditedWorlds[starID]
+	}
+
+	return g.worlds[starID]
+}
@@ -0,0 +1,141 @@
+package galaxy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCampaign(t *testing.T) {
+	g := New(Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}}, DefaultRules)
+	generatedUWP := g.World(5).UWP()
+	generatedJumps := len(g.Jumps)
+	cut := g.Jumps[0]
+	from, to := g.Stars[cut.S1ID].Key(), g.Stars[cut.S2ID].Key()
+
+	c := NewCampaign()
+	edit, err := ParseUWP("A9A6AA9-F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Star(g.Stars[5].Key()).World = edit.Changes(g.World(5))
+	c.Star(g.Stars[5].Key()).Name = "Capital"
+	c.Star(g.Stars[5].Key()).Note = "Conquered in 1105"
+	c.RemoveJump(from, to)
+	c.AddJump(g.Stars[5].Key(), g.Stars[6].Key())
+	c.Star("40000,0,0/3").Note = "Far away"
+
+	var saved bytes.Buffer
+	if err = c.WriteJSON(&saved); err != nil {
+		t.Fatal(err)
+	}
+	c, err = ReadCampaign(&saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetCampaign(c)
+	world := g.World(5)
+	if world.UWP() != "A9A6AA9-F" || world.Population < 1e10 || world.Government != govByBase[10] ||
+		g.Unedited(5).UWP() != generatedUWP {
+		t.Fatalf("edited world is %s, %d people, %s; unedited %s", world.UWP(), world.Population, world.Government,
+			g.Unedited(5).UWP())
+	}
+	if g.Name(5) != "Capital" || g.Note(5) != "Conquered in 1105" || g.Note(6) != "" {
+		t.Fatalf("star 5 named %q with note %q", g.Name(5), g.Note(5))
+	}
+	joined := func(a, b int) bool {
+		for _, jump := range g.Jumps {
+			if pairOf(jump) == pairOf(&Jump{S1ID: a, S2ID: b}) {
+				return true
+			}
+		}
+		return false
+	}
+	if joined(cut.S1ID, cut.S2ID) || !joined(5, 6) {
+		t.Fatalf("cut jump still drawn or added jump missing")
+	}
+
+	// Taking the edits out of the campaign undoes them.
+	c.AddJump(from, to)
+	c.RemoveJump(g.Stars[5].Key(), g.Stars[6].Key())
+	c.Star(g.Stars[5].Key()).World = nil
+	c.Star(g.Stars[5].Key()).Name = ""
+	c.Star(g.Stars[5].Key()).Note = ""
+	c.Tidy(g.Stars[5].Key())
+	g.SetCampaign(c)
+	if len(c.Stars) != 1 || len(c.AddJumps)+len(c.RemoveJumps) != 0 {
+		t.Fatalf("campaign left with %d stars, %d jumps added and %d cut", len(c.Stars), len(c.AddJumps),
+			len(c.RemoveJumps))
+	}
+	if g.World(5).UWP() != generatedUWP || g.Name(5) != g.Stars[5].Name() || len(g.Jumps) != generatedJumps {
+		t.Fatalf("undone campaign leaves %s named %q and %d jumps", g.World(5).UWP(), g.Name(5), len(g.Jumps))
+	}
+}
+
+func TestCampaignLongJump(t *testing.T) {
+	g := New(Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}}, DefaultRules)
+	g.SetCampaign(nil)
+	far := -1
+	for id := range g.Stars {
+		if distance(g.Stars[0], g.Stars[id])*100*parsecsPerLightYear >= 2*shortJump {
+			far = id
+			break
+		}
+	}
+	if far < 0 {
+		t.Fatal("no star far enough from star 0")
+	}
+	c := NewCampaign()
+	c.AddJump(g.Stars[0].Key(), g.Stars[far].Key())
+	g.SetCampaign(c)
+	filed := func(list []*Jump) bool {
+		for _, jump := range list {
+			if pairOf(jump) == pairOf(&Jump{S1ID: 0, S2ID: far}) {
+				return true
+			}
+		}
+		return false
+	}
+	if !filed(g.JumpsByStar[0]) || !filed(g.JumpsByStar[far]) {
+		t.Fatal("added long jump not filed under both its stars")
+	}
+	if !filed(g.TraceJumps(0)) {
+		t.Fatal("added jump not traced")
+	}
+}
+
+func TestImportNames(t *testing.T) {
+	names, err := ReadNames(strings.NewReader(`{"0,0,0/5": "Regina", "0,0,0/6": "Efate", "0,0,0/7": "", "Rhylanor": "x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewCampaign()
+	c.Star("0,0,0/6").Name = "Capital"
+	if imported := c.ImportNames(names); imported != 1 {
+		t.Fatalf("imported %d names, want 1", imported)
+	}
+	if c.Stars["0,0,0/5"].Name != "Regina" || c.Stars["0,0,0/6"].Name != "Capital" || len(c.Stars) != 2 {
+		t.Fatalf("stars named %q and %q of %d edited", c.Stars["0,0,0/5"].Name, c.Stars["0,0,0/6"].Name, len(c.Stars))
+	}
+	if err = c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadCampaignRejects(t *testing.T) {
+	tests := map[string]string{
+		"future version": `{"version": 99}`,
+		"bad key":        `{"version": 1, "stars": {"Regina": {"name": "Regina"}}}`,
+		"bad starport":   `{"version": 1, "stars": {"0,0,0/1": {"world": {"starPort": "Q"}}}}`,
+		"bad law":        `{"version": 1, "stars": {"0,0,0/1": {"world": {"law": 12}}}}`,
+		"loop jump":      `{"version": 1, "addJumps": [{"from": "0,0,0/1", "to": "0,0,0/1"}]}`,
+	}
+	for name, doc := range tests {
+		if _, err := ReadCampaign(strings.NewReader(doc)); err == nil {
+			t.Errorf("%s: read without error", name)
+		}
+	}
+	if _, err := ParseUWP("A78889-C"); err == nil {
+		t.Errorf("short UWP parsed without error")
+	}
+}
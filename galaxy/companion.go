@@ -0,0 +1,147 @@
+package galaxy
+
+import (
+	"fmt"
+	"image/color"
+	"math/rand"
+	"strings"
+
+	"github.com/chewxy/math32"
+)
+
+// companionSeed sets companion dice apart from the star's other dice.
+const companionSeed = 0x636f6d70
+
+// Orbit zones for companions, by separation.
+const (
+	CloseOrbit = "Close"
+	NearOrbit  = "Near"
+	FarOrbit   = "Far"
+)
+
+const (
+	// closeSeparation and farSeparation, in AU, divide the orbit zones.
+	closeSeparation = 1
+	farSeparation   = 100
+	// minSeparation and maxSeparation, in AU, bound companion orbits.
+	minSeparation = 0.05
+	maxSeparation = 2000
+)
+
+// Companion is a star orbiting a system's primary.
+type Companion struct {
+	Class       string     `json:"class"`
+	Subtype     int        `json:"subtype"`
+	Luminosity  string     `json:"luminosity"`
+	BrightColor color.RGBA `json:"-"`
+	Mass        float32    `json:"mass"`
+	Radii       float32    `json:"radii"`
+	Luminance   float32    `json:"luminance"`
+	// Separation is the orbit's radius in AU and Period its length in years.
+	Separation float32 `json:"separation"`
+	Period     float32 `json:"period"`
+	Orbit      string  `json:"orbit"`
+}
+
+// getCompanions rolls a star's companions. The class's multiplicity is the
+// chance of a binary, and a third of it the chance a binary is a trinary.
+// Companions are the primary's class or dimmer, in proportion to how common
+// each class is, and orbit from minSeparation to maxSeparation AU with every
+// power of ten equally likely.
+func getCompanions(star *Star, classes []classDetails) (companions []*Companion) {
+	primary, ok := classDetailsOf(star.Class)
+	if !ok {
+		return
+	}
+	dice := starDice(star, companionSeed)
+	count := 0
+	if dice.Float32() < primary.multiplicity {
+		count++
+		if dice.Float32() < primary.multiplicity/3 {
+			count++
+		}
+	}
+	for i := 0; i < count; i++ {
+		details := companionClass(star.Class, classes, dice)
+		roll := dice.Float32()
+		companion := &Companion{
+			Class:       details.class,
+			Subtype:     subtype(roll),
+			Luminosity:  details.luminosity,
+			BrightColor: details.brightColor,
+			Mass:        details.minMass + details.deltaMass*(1+roll),
+			Radii:       (details.minRadii + roll*details.deltaRadii) / 2,
+			Luminance:   details.minLum + roll*details.deltaLum,
+			Separation:  minSeparation * math32.Pow(maxSeparation/minSeparation, dice.Float32()),
+		}
+		companion.Period = math32.Sqrt(companion.Separation * companion.Separation * companion.Separation /
+			(star.Mass + companion.Mass))
+		companion.Orbit = orbitZone(companion.Separation)
+		companions = append(companions, companion)
+	}
+
+	return
+}
+
+// companionClass picks a class no brighter than the primary's, that is no
+// earlier in classes.
+func companionClass(primary string, classes []classDetails, dice *rand.Rand) classDetails {
+	first := len(classes) - 1
+	for i, details := range classes {
+		if details.class == primary {
+			first = i
+			break
+		}
+	}
+	total := float32(0)
+	for _, details := range classes[first:] {
+		total += details.odds
+	}
+	roll := dice.Float32() * total
+	for _, details := range classes[first:] {
+		roll -= details.odds
+		if roll < 0 {
+			return details
+		}
+	}
+
+	return classes[len(classes)-1]
+}
+
+func orbitZone(separation float32) string {
+	switch {
+	case separation < closeSeparation:
+		return CloseOrbit
+	case separation < farSeparation:
+		return NearOrbit
+	default:
+		return FarOrbit
+	}
+}
+
+// HasCloseCompanion reports whether a companion orbits within 1 AU.
+func (s *Star) HasCloseCompanion() bool {
+	for _, companion := range s.Companions {
+		if companion.Orbit == CloseOrbit {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Spectral is the companion's spectral type, like Star.Spectral.
+func (c *Companion) Spectral() string {
+	return spectral(c.Class, c.Subtype, c.Luminosity)
+}
+
+// CompanionList describes the companions, such as "K3 V Near 12.3 AU; D Far 340.0 AU".
+func (s *Star) CompanionList() string {
+	companions := make([]string, 0)
+	for _, companion := range s.Companions {
+		companions = append(companions, fmt.Sprintf("%s %s %.1f AU", companion.Spectral(), companion.Orbit,
+			companion.Separation))
+	}
+
+	return strings.Join(companions, "; ")
+}
@@ -0,0 +1,41 @@
+package galaxy
+
+import (
+	"testing"
+)
+
+// classOrder is a class's place in extendedClasses, brightest first.
+func classOrder(class string) int {
+	for i, details := range extendedClasses {
+		if details.class == class {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func TestCompanions(t *testing.T) {
+	multiples := 0
+	stars := SectorStars(Sector{X: 3, Y: -2, Z: 1}, DefaultRules)
+	for _, star := range stars {
+		if len(star.Companions) > 2 {
+			t.Fatalf("star %s has %d companions", star.Key(), len(star.Companions))
+		}
+		if len(star.Companions) > 0 {
+			multiples++
+		}
+		for _, companion := range star.Companions {
+			if classOrder(companion.Class) < classOrder(star.Class) {
+				t.Errorf("star %s: %s companion of a %s star", star.Key(), companion.Class, star.Class)
+			}
+			if companion.Separation < minSeparation || companion.Separation > maxSeparation ||
+				companion.Orbit != orbitZone(companion.Separation) {
+				t.Errorf("star %s: %s orbit at %f AU", star.Key(), companion.Orbit, companion.Separation)
+			}
+		}
+	}
+	if multiples == 0 || multiples > len(stars)/2 {
+		t.Fatalf("%d of %d stars have companions", multiples, len(stars))
+	}
+}
@@ -0,0 +1,98 @@
+package galaxy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chewxy/math32"
+)
+
+// Density is how many stars a sector holds relative to the sectors around
+// sector 0,0,0, which hold 1.
+type Density func(Sector) float32
+
+// Densities are the density models Rules can name. Add to it to plug in
+// another.
+var Densities = map[string]Density{
+	"uniform":  Uniform,
+	"galactic": Galactic,
+}
+
+// Uniform puts as many stars in every sector, as the first versions did.
+func Uniform(Sector) float32 {
+	return 1
+}
+
+// The galactic model's shape, with lengths in sectors (100 light years).
+// Sector 0,0,0 sits in the midplane of the disk, halfway between two arms,
+// with the centre 267 sectors away along +X; Y is up out of the disk.
+const (
+	solarRadius = 267
+	// minRadius keeps the centre, which the model doesn't describe, finite.
+	minRadius = 10
+	// thinHeight and thickHeight are the disks' exponential scale heights,
+	// thinLength and thickLength their radial scale lengths.
+	thinHeight  = 10
+	thickHeight = 30
+	thinLength  = 85
+	thickLength = 115
+	// thickShare is the thick disk's share of the stars near the Sun.
+	thickShare = 0.12
+	// The thin disk's stars gather in armCount logarithmic spiral arms,
+	// pitched armPitch radians, as much as armContrast denser than average
+	// along their crests.
+	armCount    = 4
+	armPitch    = 0.21
+	armContrast = 0.5
+)
+
+// Galactic models a spiral galaxy seen from the Sun: a thin and a thick disk,
+// each falling off exponentially with height and with distance from the
+// centre, and the thin disk's stars bunched into spiral arms.
+func Galactic(s Sector) float32 {
+	x := float32(s.X) + .5 - solarRadius
+	y := math32.Abs(float32(s.Y) + .5)
+	z := float32(s.Z) + .5
+	radius := math32.Sqrt(x*x + z*z)
+	if radius < minRadius {
+		radius = minRadius
+	}
+	thin := math32.Exp(-y/thinHeight-(radius-solarRadius)/thinLength) * spiralArms(radius, math32.Atan2(z, -x))
+	thick := math32.Exp(-y/thickHeight - (radius-solarRadius)/thickLength)
+
+	return (1-thickShare)*thin + thickShare*thick
+}
+
+// spiralArms is how much denser than average the thin disk is at the given
+// radius and angle around the centre, 1 on average around each circle. The
+// Sun, at angle 0, is halfway between arms.
+func spiralArms(radius, angle float32) float32 {
+	phase := armCount * (angle - math32.Log(radius/solarRadius)/math32.Tan(armPitch))
+
+	return 1 + armContrast*math32.Sin(phase)
+}
+
+// DensityNames lists the models in Densities, in alphabetical order.
+func DensityNames() string {
+	names := make([]string, 0, len(Densities))
+	for name := range Densities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// densityOf looks up a density model by name; empty is uniform.
+func densityOf(name string) (density Density, err error) {
+	if name == "" {
+		name = "uniform"
+	}
+	density, ok := Densities[name]
+	if !ok {
+		err = fmt.Errorf("density %q should be one of %s", name, DensityNames())
+	}
+
+	return
+}
@@ -0,0 +1,97 @@
+package galaxy
+
+import (
+	"testing"
+
+	"github.com/chewxy/math32"
+)
+
+// sectorAt is the sector at radius sectors from the galactic centre, angle
+// radians around from the Sun's direction, height sectors above the disk.
+func sectorAt(radius, angle float32, height int32) Sector {
+	sin, cos := math32.Sincos(angle)
+
+	return Sector{X: int32(math32.Floor(solarRadius - radius*cos)), Y: height, Z: int32(math32.Floor(radius * sin))}
+}
+
+func TestGalacticShape(t *testing.T) {
+	near := Galactic(Sector{X: 0, Y: 0, Z: 0})
+	if math32.Abs(near-1) > .1 {
+		t.Errorf("density at the Sun is %f, want about 1", near)
+	}
+	if up, down := Galactic(Sector{X: 0, Y: 9, Z: 0}), Galactic(Sector{X: 0, Y: -10, Z: 0}); up != down {
+		t.Errorf("density %f above the disk and %f below", up, down)
+	}
+	for height := int32(0); height < 60; height += 10 {
+		if Galactic(Sector{X: 0, Y: height + 10, Z: 0}) >= Galactic(Sector{X: 0, Y: height, Z: 0}) {
+			t.Errorf("density doesn't fall from height %d to %d", height, height+10)
+		}
+	}
+
+	// Around each circle the arms average out, leaving the radial falloff.
+	previous := float32(0)
+	for _, radius := range []float32{300, 200, 100} {
+		total := float32(0)
+		for step := 0; step < 360; step++ {
+			total += spiralArms(radius, 2*math32.Pi*float32(step)/360)
+		}
+		if math32.Abs(total/360-1) > .01 {
+			t.Errorf("arms average %f around radius %f, want 1", total/360, radius)
+		}
+		average := float32(0)
+		for step := 0; step < 360; step++ {
+			average += Galactic(sectorAt(radius, 2*math32.Pi*float32(step)/360, 0)) / 360
+		}
+		if average <= previous {
+			t.Errorf("average density %f at radius %f isn't above %f further out", average, radius, previous)
+		}
+		previous = average
+	}
+}
+
+// expectedStars is the mean number of stars the rules put in a sector of the
+// given density.
+func expectedStars(rules Rules, density float32) (expected float32) {
+	for _, details := range rules.classes() {
+		expected += 800 * density * details.odds
+	}
+
+	return
+}
+
+// TestStarCountsFollowDensity generates blocks of sectors in differently
+// crowded parts of the galaxy and checks the stars counted in each against
+// the model.
+func TestStarCountsFollowDensity(t *testing.T) {
+	rules := Rules{Density: "galactic"}
+	crest := sectorAt(solarRadius, math32.Pi/8, 0)
+	trough := sectorAt(solarRadius, -math32.Pi/8, 0)
+	zones := map[string]Sector{
+		"solar neighbourhood": {X: 0, Y: 0, Z: 0},
+		"above the disk":      {X: 0, Y: 15, Z: 0},
+		"inner disk":          {X: 150, Y: 0, Z: 0},
+		"arm crest":           crest,
+		"between arms":        trough,
+	}
+	counts := make(map[string]float32)
+	for name, corner := range zones {
+		t.Run(name, func(t *testing.T) {
+			block := Region{From: corner, To: Sector{X: corner.X + 3, Y: corner.Y + 1, Z: corner.Z + 1}}
+			stars, expected := 0, float32(0)
+			for _, sector := range block.Sectors() {
+				stars += len(SectorStars(sector, rules))
+				expected += expectedStars(rules, Galactic(sector))
+			}
+			counts[name] = float32(stars)
+			// Each class's count is rounded down, so a sparse sector loses up
+			// to a star of each class.
+			low := expected - float32(len(block.Sectors())*len(rules.classes()))
+			if float32(stars) < .95*low || float32(stars) > 1.05*expected {
+				t.Fatalf("%d stars in %v, want %.0f to %.0f", stars, block, low, expected)
+			}
+		})
+	}
+	if counts["arm crest"] < 2*counts["between arms"] {
+		t.Errorf("%.0f stars on an arm and %.0f between arms", counts["arm crest"], counts["between arms"])
+	}
+}
@@ -0,0 +1,344 @@
+package galaxy
+
+// MaxTech returns the stars whose worlds share the highest tech level.
+func (g *Galaxy) MaxTech() (results []*Star) {
+	techMax := -99
+	empty := make([]*Star, 0)
+	results = empty
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.TechLevelBase > techMax {
+			techMax = world.TechLevelBase
+			results = append(empty, star)
+		} else if world.TechLevelBase == techMax {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsByTech returns the stars whose worlds have exactly the given tech level.
+func (g *Galaxy) StarsByTech(tech int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.TechLevelBase == tech {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsTechAtLeast returns the stars whose worlds have at least the given tech level.
+func (g *Galaxy) StarsTechAtLeast(tech int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.TechLevelBase >= tech {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsTechAtMost returns the stars whose worlds have at most the given tech level.
+func (g *Galaxy) StarsTechAtMost(tech int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.TechLevelBase <= tech {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// MaxPop returns the stars whose worlds share the highest population code.
+func (g *Galaxy) MaxPop() (results []*Star) {
+	popMax := -99
+	empty := make([]*Star, 0)
+	results = empty
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.PopBase > popMax {
+			popMax = world.PopBase
+			results = append(empty, star)
+		} else if world.PopBase == popMax {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// MinPop returns the stars whose worlds share the lowest population code.
+func (g *Galaxy) MinPop() (results []*Star) {
+	popMin := 199
+	empty := make([]*Star, 0)
+	results = empty
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.PopBase < popMin {
+			popMin = world.PopBase
+			results = append(empty, star)
+		} else if world.PopBase == popMin {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsByPop returns the stars whose worlds have exactly the given population code.
+func (g *Galaxy) StarsByPop(pop int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.PopBase == pop {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsPopAtLeast returns the stars whose worlds have at least the given population code.
+func (g *Galaxy) StarsPopAtLeast(pop int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.PopBase >= pop {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsPopAtMost returns the stars whose worlds have at most the given population code.
+func (g *Galaxy) StarsPopAtMost(pop int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.PopBase <= pop {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// AllStars returns every star.
+func (g *Galaxy) AllStars() (results []*Star) {
+	results = g.Stars
+
+	return
+}
+
+// MaxSize returns the stars whose worlds share the largest size code.
+func (g *Galaxy) MaxSize() (results []*Star) {
+	sizeMax := -99
+	empty := make([]*Star, 0)
+	results = empty
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.SizeBase > sizeMax {
+			sizeMax = world.SizeBase
+			results = append(empty, star)
+		} else if world.SizeBase == sizeMax {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// MinSize returns the stars whose worlds share the smallest size code.
+func (g *Galaxy) MinSize() (results []*Star) {
+	sizeMin := 99
+	empty := make([]*Star, 0)
+	results = empty
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.SizeBase < sizeMin {
+			sizeMin = world.SizeBase
+			results = append(empty, star)
+		} else if world.SizeBase == sizeMin {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsBySize returns the stars whose worlds have exactly the given size code.
+func (g *Galaxy) StarsBySize(size int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.SizeBase == size {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsSizeAtMost returns the stars whose worlds have at most the given size code.
+func (g *Galaxy) StarsSizeAtMost(size int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.SizeBase <= size {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsSizeAtLeast returns the stars whose worlds have at least the given size code.
+func (g *Galaxy) StarsSizeAtLeast(size int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.SizeBase >= size {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarHydroMax returns the stars whose worlds share the highest hydrographic code.
+func (g *Galaxy) StarHydroMax() (results []*Star) {
+	hydroMax := -100
+	empty := make([]*Star, 0)
+	results = empty
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.HydroBase > hydroMax {
+			hydroMax = world.HydroBase
+			results = append(empty, star)
+		} else if world.HydroBase == hydroMax {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarHydroMin returns the stars whose worlds share the lowest hydrographic code.
+func (g *Galaxy) StarHydroMin() (results []*Star) {
+	hydroMin := 300
+	empty := make([]*Star, 0)
+	results = empty
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.HydroBase < hydroMin {
+			hydroMin = world.HydroBase
+			results = append(empty, star)
+		} else if world.HydroBase == hydroMin {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarHydroAtLeast returns the stars whose worlds have at least the given hydrographic code.
+func (g *Galaxy) StarHydroAtLeast(hydroMin int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.HydroBase >= hydroMin {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarHydroAtMost returns the stars whose worlds have at most the given hydrographic code.
+func (g *Galaxy) StarHydroAtMost(hydroMax int) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.HydroBase <= hydroMax {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsWithTradeCode returns the stars whose worlds have the trade classification.
+func (g *Galaxy) StarsWithTradeCode(code TradeCode) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		world := g.World(star.ID)
+		if world.HasTradeCode(code) {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsInZone returns the stars whose worlds are in the travel zone.
+func (g *Galaxy) StarsInZone(zone Zone) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		if g.World(star.ID).Zone == zone {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsWithBase returns the stars whose worlds have the base.
+func (g *Galaxy) StarsWithBase(base Base) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		if g.HasBase(star.ID, base) {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsOnTradeRoute returns the stars at either end of a trade route of the
+// class.
+func (g *Galaxy) StarsOnTradeRoute(route TradeRoute) (results []*Star) {
+	results = make([]*Star, 0)
+	on := make(map[int]bool)
+	for _, jump := range g.TradeRouteJumps(route) {
+		on[jump.S1ID], on[jump.S2ID] = true, true
+	}
+	for _, star := range g.Stars {
+		if on[star.ID] {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+// StarsOfPolity returns the stars held by the polity with the allegiance
+// code, or the non-aligned stars for NonAligned.
+func (g *Galaxy) StarsOfPolity(code string) (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		if g.AllegianceCode(star.ID) == code {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
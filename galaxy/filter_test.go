@@ -0,0 +1,56 @@
+package galaxy
+
+import "testing"
+
+// uncached rerolls every world on each call, as the filters did before the
+// world table, for comparison.
+func uncached(g *Galaxy) (results []*Star) {
+	techMax := -99
+	for _, star := range g.Stars {
+		world := worldFromStar(star)
+		if world.TechLevelBase > techMax {
+			techMax = world.TechLevelBase
+			results = []*Star{star}
+		} else if world.TechLevelBase == techMax {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+func TestWorldsMatchRerolled(t *testing.T) {
+	g := New(cube(2), DefaultRules)
+	for id, star := range g.Stars {
+		if *g.World(id) != *worldFromStar(star) {
+			t.Fatalf("star %d: cached world differs from a fresh roll", id)
+		}
+	}
+}
+
+func BenchmarkFilters(b *testing.B) {
+	g := New(cube(2), DefaultRules)
+	filters := map[string]func(g *Galaxy) []*Star{
+		"MaxTech":      (*Galaxy).MaxTech,
+		"MaxPop":       (*Galaxy).MaxPop,
+		"MinPop":       (*Galaxy).MinPop,
+		"MaxSize":      (*Galaxy).MaxSize,
+		"StarHydroMax": (*Galaxy).StarHydroMax,
+		"Uncached":     uncached,
+	}
+	for name, filter := range filters {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				filter(g)
+			}
+		})
+	}
+}
+
+// BenchmarkBuildWorlds is the one-off cost the filters save on every call.
+func BenchmarkBuildWorlds(b *testing.B) {
+	g := New(cube(2), DefaultRules)
+	for i := 0; i < b.N; i++ {
+		g.buildWorlds()
+	}
+}
@@ -0,0 +1,168 @@
+// Package galaxy procedurally generates sectors of stars, the Traveller
+// style worlds around them and the jump routes that link them. It has no
+// GUI dependencies; galaxy3d is one client of it.
+package galaxy
+
+// Galaxy holds a generated block of stars and the jumps between them.
+// Stars are numbered by their index in Stars.
+type Galaxy struct {
+	Stars []*Star
+	// Jumps is every route drawn between stars, at most three per star.
+	Jumps []*Jump
+	// JumpsByStar holds the routes short enough to travel, by star ID.
+	JumpsByStar map[int][]*Jump
+
+	// worlds is each star's mainworld by star ID, rolled once when the galaxy
+	// is built.
+	worlds []*World
+	// systems are the systems read from a file by star ID, nil when they are
+	// generated as they are asked for.
+	systems []*System
+	// names are the names players gave stars, and notes the notes loaded
+	// with them, by star Key.
+	names Names
+	notes map[string]string
+	// campaign is laid over the worlds and jumps as they were before it,
+	// kept in uneditedWorlds and uneditedJumps.
+	campaign       *Campaign
+	uneditedWorlds []*World
+	uneditedJumps  []*Jump
+	// xboats is the X-boat network, nil until it is asked for and whenever
+	// the worlds or jumps change.
+	xboats *xboatNetwork
+	// trade is the trade carried over each jump, nil like xboats.
+	trade tradeNetwork
+	// polities are the polities and every star's allegiance, nil like
+	// xboats.
+	polities *polityMap
+	// around is the galaxy with the sectors around it, nil like xboats.
+	around *surroundings
+	// sectors generates the sectors around the galaxy, which the X-boat
+	// network runs through and polities are founded in too. It is nil for a
+	// galaxy read from a file or built from given stars.
+	sectors *SectorCache
+}
+
+// New generates every sector in the region under the rules and links the
+// stars with jumps.
+func New(region Region, rules Rules) *Galaxy {
+	stars := make([]*Star, 0)
+	for _, sector := range region.Sectors() {
+		stars = append(stars, SectorStars(sector, rules)...)
+	}
+	g := FromStars(stars)
+	g.sectors = NewSectorCache(rules)
+
+	return g
+}
+
+// FromStars numbers the given stars in order and links them with jumps.
+func FromStars(stars []*Star) *Galaxy {
+	g := &Galaxy{Stars: stars}
+	for id, star := range g.Stars {
+		star.ID = id
+	}
+	g.buildJumps()
+	g.buildWorlds()
+
+	return g
+}
+
+// Find returns the ID of the star with the given index in the given sector,
+// if that sector is in the galaxy.
+func (g *Galaxy) Find(sector Sector, index int) (starID int, ok bool) {
+	for _, star := range g.Stars {
+		if star.Sector == sector && star.Index == index {
+			return star.ID, true
+		}
+	}
+
+	return -1, false
+}
+
+// Region is the smallest region holding every star's sector.
+func (g *Galaxy) Region() (r Region) {
+	for id, star := range g.Stars {
+		if id == 0 {
+			r = Region{From: star.Sector, To: star.Sector}
+		}
+		r = r.extend(star.Sector)
+	}
+
+	return
+}
+
+// Nearest returns the ID of the star closest to a position, or -1 if there
+// are no stars.
+func (g *Galaxy) Nearest(p Position) (starID int) {
+	starID = -1
+	best := float32(0)
+	for id, star := range g.Stars {
+		d := (star.X-p.X)*(star.X-p.X) + (star.Y-p.Y)*(star.Y-p.Y) + (star.Z-p.Z)*(star.Z-p.Z)
+		if starID < 0 || d < best {
+			starID = id
+			best = d
+		}
+	}
+
+	return
+}
+
+// World returns the mainworld of the star with the given ID. Worlds are
+// rolled once, when the galaxy is built, and shared by every caller.
+func (g *Galaxy) World(starID int) *World {
+	return g.worlds[starID]
+}
+
+func (g *Galaxy) buildWorlds() {
+	g.xboats, g.trade, g.polities, g.around = nil, nil, nil, nil
+	g.worlds = make([]*World, len(g.Stars))
+	for id, star := range g.Stars {
+		g.worlds[id] = worldFromStar(star)
+	}
+}
+
+// ConnectedStar returns a star in the largest jump network and the number of
+// jumps in that network.
+func (g *Galaxy) ConnectedStar() (starID int, jumps int) {
+	jumps = -1
+	for id := range g.Stars {
+		tJumps := g.TraceJumps(id)
+		if len(tJumps) > jumps {
+			jumps = len(tJumps)
+			starID = id
+		}
+	}
+
+	return
+}
+
+// ConnectedWorlds lists the worlds reachable from the given star, each one
+// once, in the order TraceJumps reaches them.
+func (g *Galaxy) ConnectedWorlds(starID int) (result []*World) {
+	result = make([]*World, 0)
+	alreadyListed := make([]int, 0)
+	for _, nextJump := range g.TraceJumps(starID) {
+		if !contains(alreadyListed, nextJump.S1ID) && nextJump.S1ID > -1 {
+			result = append(result, g.World(nextJump.S1ID))
+			alreadyListed = append(alreadyListed, nextJump.S1ID)
+		}
+		if !contains(alreadyListed, nextJump.S2ID) && nextJump.S2ID > -1 {
+			result = append(result, g.World(nextJump.S2ID))
+			alreadyListed = append(alreadyListed, nextJump.S2ID)
+		}
+	}
+
+	return
+}
+
+func contains(soFar []int, next int) (yes bool) {
+	yes = false
+	for _, sID := range soFar {
+		if sID == next {
+			yes = true
+			break
+		}
+	}
+	return
+}
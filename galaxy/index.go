@@ -0,0 +1,63 @@
+package galaxy
+
+import (
+	"sort"
+
+	"github.com/chewxy/math32"
+)
+
+// maxJumpDistance is the longest jump checkFor1jump accepts, in sectors.
+var maxJumpDistance = float32(len(jumpColors)) / (100 * parsecsPerLightYear)
+
+type cell struct {
+	x int32
+	y int32
+	z int32
+}
+
+// starIndex is a uniform grid over star positions. Cells are a little wider
+// than the longest jump, so every star within jump range of another lies in
+// the same cell or one of its 26 neighbours.
+type starIndex struct {
+	cellSize float32
+	cells    map[cell][]int
+}
+
+func newStarIndex(stars []*Star, radius float32) *starIndex {
+	ix := &starIndex{cellSize: radius * 1.001, cells: make(map[cell][]int)}
+	for id, star := range stars {
+		c := ix.cellOf(star)
+		ix.cells[c] = append(ix.cells[c], id)
+	}
+
+	return ix
+}
+
+func (ix *starIndex) cellOf(star *Star) cell {
+	return cell{
+		x: int32(math32.Floor(star.X / ix.cellSize)),
+		y: int32(math32.Floor(star.Y / ix.cellSize)),
+		z: int32(math32.Floor(star.Z / ix.cellSize)),
+	}
+}
+
+// after returns, in ascending order, the IDs above id of the stars in the
+// cells around star.
+func (ix *starIndex) after(star *Star, id int) (result []int) {
+	result = make([]int, 0)
+	c := ix.cellOf(star)
+	for x := c.x - 1; x <= c.x+1; x++ {
+		for y := c.y - 1; y <= c.y+1; y++ {
+			for z := c.z - 1; z <= c.z+1; z++ {
+				for _, innerID := range ix.cells[cell{x: x, y: y, z: z}] {
+					if innerID > id {
+						result = append(result, innerID)
+					}
+				}
+			}
+		}
+	}
+	sort.Ints(result)
+
+	return
+}
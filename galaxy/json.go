@@ -0,0 +1,271 @@
+package galaxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+)
+
+// FormatVersion is the version of the JSON written by WriteJSON. ReadJSON
+// reads this version and earlier ones. Version 2 added companion stars and
+// version 3 spectral subtypes and luminosity classes, version 4 star
+// systems, version 5 star names, version 6 notes, version 7 travel zones
+// and version 8 the campaign.
+const FormatVersion = 8
+
+// galaxyJSON is the whole JSON document. Stars are numbered by their place
+// in the list, and worlds and jumps refer to stars by that number. The
+// stars, worlds and jumps are the galaxy as generated or loaded, and the
+// campaign's edits are kept apart from them.
+type galaxyJSON struct {
+	Version int         `json:"version"`
+	Sectors []Sector    `json:"sectors"`
+	Stars   []starJSON  `json:"stars"`
+	Worlds  []*World    `json:"worlds"`
+	Jumps   []*jumpJSON `json:"jumps"`
+	// Systems is new in version 4. Stars without one have it generated.
+	Systems []*System `json:"systems,omitempty"`
+	// Campaign is new in version 8.
+	Campaign *Campaign `json:"campaign,omitempty"`
+}
+
+// starJSON holds what generation decided about a star. Its colors and size
+// on screen follow from its class.
+type starJSON struct {
+	Sector Sector `json:"sector"`
+	Index  int    `json:"index"`
+	// Name is new in version 5. Names that differ from the generated one
+	// are kept as names players gave the star.
+	Name string `json:"name,omitempty"`
+	// Note is new in version 6.
+	Note  string `json:"note,omitempty"`
+	Class string `json:"class"`
+	// Subtype and Luminosity are new in version 3.
+	Subtype    int      `json:"subtype"`
+	Luminosity string   `json:"luminosity"`
+	Mass       float32  `json:"mass"`
+	Radii      float32  `json:"radii"`
+	Luminance  float32  `json:"luminance"`
+	Position   Position `json:"position"`
+	// Companions is new in version 2.
+	Companions []*Companion `json:"companions,omitempty"`
+}
+
+type jumpJSON struct {
+	From     int     `json:"from"`
+	To       int     `json:"to"`
+	Parsecs  int     `json:"parsecs"`
+	Distance float32 `json:"distance"`
+}
+
+// WriteJSON writes every star, world and jump of the galaxy, and the sectors
+// they came from, as versioned JSON that ReadJSON reads back. They are written
+// as generated or loaded, with the campaign alongside to lay over them again.
+func (g *Galaxy) WriteJSON(w io.Writer) error {
+	worlds, jumps := g.worlds, g.Jumps
+	if g.uneditedWorlds != nil {
+		worlds, jumps = g.uneditedWorlds, g.uneditedJumps
+	}
+	doc := galaxyJSON{
+		Version: FormatVersion,
+		Sectors: make([]Sector, 0),
+		Stars:   make([]starJSON, 0, len(g.Stars)),
+		Worlds:  worlds,
+		Jumps:   make([]*jumpJSON, 0, len(jumps)),
+		Systems: make([]*System, 0, len(g.Stars)),
+	}
+	if !g.Campaign().Empty() {
+		doc.Campaign = g.campaign
+	}
+	seen := make(map[Sector]bool)
+	for _, star := range g.Stars {
+		if !seen[star.Sector] {
+			seen[star.Sector] = true
+			doc.Sectors = append(doc.Sectors, star.Sector)
+		}
+		name, ok := g.names[star.Key()]
+		if !ok {
+			name = star.Name()
+		}
+		doc.Stars = append(doc.Stars, starJSON{
+			Sector:     star.Sector,
+			Index:      star.Index,
+			Name:       name,
+			Note:       g.notes[star.Key()],
+			Class:      star.Class,
+			Subtype:    star.Subtype,
+			Luminosity: star.Luminosity,
+			Mass:       star.Mass,
+			Radii:      star.Radii,
+			Luminance:  star.Luminance,
+			Position:   Position{X: star.X, Y: star.Y, Z: star.Z},
+			Companions: star.Companions,
+		})
+	}
+	for id, star := range g.Stars {
+		if g.systems != nil && g.systems[id] != nil {
+			doc.Systems = append(doc.Systems, g.systems[id])
+		} else {
+			doc.Systems = append(doc.Systems, systemFromStar(star, g.Unedited(id)))
+		}
+	}
+	for _, jump := range jumps {
+		doc.Jumps = append(doc.Jumps, &jumpJSON{From: jump.S1ID, To: jump.S2ID, Parsecs: jump.Parsecs, Distance: jump.Distance})
+	}
+	out := json.NewEncoder(w)
+	out.SetIndent("", " ")
+
+	return out.Encode(doc)
+}
+
+// ReadJSON rebuilds a galaxy from JSON written by WriteJSON, or edited by
+// hand, without generating anything: the stars, worlds and jumps are exactly
+// those in the file, with its campaign, if any, laid over them.
+func ReadJSON(r io.Reader) (g *Galaxy, err error) {
+	var doc galaxyJSON
+	err = json.NewDecoder(r).Decode(&doc)
+	if err != nil {
+		return
+	}
+	if doc.Version < 1 || doc.Version > FormatVersion {
+		return nil, fmt.Errorf("galaxy JSON version %d, can read 1 to %d", doc.Version, FormatVersion)
+	}
+
+	g = &Galaxy{Stars: make([]*Star, 0, len(doc.Stars)), names: make(Names),
+		notes: make(map[string]string)}
+	for id, record := range doc.Stars {
+		details, ok := classDetailsOf(record.Class)
+		if !ok {
+			return nil, fmt.Errorf("star %d: unknown class %q", id, record.Class)
+		}
+		for _, companion := range record.Companions {
+			companionDetails, ok := classDetailsOf(companion.Class)
+			if !ok {
+				return nil, fmt.Errorf("star %d: companion of unknown class %q", id, companion.Class)
+			}
+			companion.BrightColor = companionDetails.brightColor
+			if doc.Version < 3 {
+				companion.Subtype, companion.Luminosity = classicSpectral(companionDetails, companion.Mass)
+			}
+		}
+		if doc.Version < 3 {
+			record.Subtype, record.Luminosity = classicSpectral(details, record.Mass)
+		}
+		g.Stars = append(g.Stars, &Star{
+			ID:          id,
+			Class:       record.Class,
+			Subtype:     record.Subtype,
+			Luminosity:  record.Luminosity,
+			BrightColor: details.brightColor,
+			DimColor:    details.dimColor,
+			Pixels:      details.pixels,
+			Mass:        record.Mass,
+			Radii:       record.Radii,
+			Luminance:   record.Luminance,
+			X:           record.Position.X,
+			Y:           record.Position.Y,
+			Z:           record.Position.Z,
+			SX:          record.Position.X - float32(record.Sector.X),
+			SY:          record.Position.Y - float32(record.Sector.Y),
+			SZ:          record.Position.Z - float32(record.Sector.Z),
+			Sector:      record.Sector,
+			Index:       record.Index,
+			Companions:  record.Companions,
+		})
+		if star := g.Stars[id]; record.Name != "" && record.Name != star.Name() {
+			g.names[star.Key()] = record.Name
+		}
+		if record.Note != "" {
+			g.notes[g.Stars[id].Key()] = record.Note
+		}
+	}
+
+	g.worlds = make([]*World, len(g.Stars))
+	for _, world := range doc.Worlds {
+		if world == nil || world.StarID < 0 || world.StarID >= len(g.Stars) {
+			return nil, fmt.Errorf("world for a star that isn't in the file")
+		}
+		if doc.Version >= 7 && !validZone(world.Zone) {
+			return nil, fmt.Errorf("star %d: unknown travel zone %q", world.StarID, world.Zone)
+		}
+		g.worlds[world.StarID] = world
+		if doc.Version < 7 {
+			world.Zone, world.ZoneReason = travelZone(world, starDice(g.Stars[world.StarID], zoneSeed))
+		}
+	}
+	for id, world := range g.worlds {
+		if world == nil {
+			return nil, fmt.Errorf("star %d has no world", id)
+		}
+	}
+
+	if len(doc.Systems) > 0 {
+		g.systems = make([]*System, len(g.Stars))
+	}
+	for _, system := range doc.Systems {
+		if system == nil || system.StarID < 0 || system.StarID >= len(g.Stars) {
+			return nil, fmt.Errorf("system for a star that isn't in the file")
+		}
+		if system.Mainworld() == nil {
+			return nil, fmt.Errorf("star %d: system has no mainworld", system.StarID)
+		}
+		g.systems[system.StarID] = system
+	}
+
+	jumps := make([]*Jump, 0, len(doc.Jumps))
+	for _, record := range doc.Jumps {
+		if record.From < 0 || record.From >= len(g.Stars) || record.To < 0 || record.To >= len(g.Stars) ||
+			record.From == record.To {
+			return nil, fmt.Errorf("jump from %d to %d doesn't join two stars in the file", record.From, record.To)
+		}
+		jumps = append(jumps, &Jump{
+			Color:    jumpColor(record.Parsecs),
+			Parsecs:  record.Parsecs,
+			Distance: record.Distance,
+			S1ID:     record.From,
+			S2ID:     record.To,
+		})
+	}
+	g.setJumps(jumps)
+
+	if doc.Campaign != nil {
+		err = doc.Campaign.check()
+		if err != nil {
+			return nil, err
+		}
+		g.SetCampaign(doc.Campaign)
+	}
+
+	return
+}
+
+// classicSpectral works out the subtype of a star saved before subtypes
+// were, from where its mass lies in its class's classic range. Such stars
+// are all main sequence.
+func classicSpectral(details classDetails, mass float32) (int, string) {
+	for _, classic := range starDetailsByClass {
+		if classic.class == details.class {
+			details = classic
+		}
+	}
+	roll := (mass-details.minMass)/details.deltaMass - 1
+	if roll < 0 {
+		roll = 0
+	} else if roll > .99 {
+		roll = .99
+	}
+
+	return subtype(roll), MainSequence
+}
+
+// jumpColor is the color drawn for a jump of the given rating.
+func jumpColor(parsecs int) color.RGBA {
+	if parsecs < 0 {
+		parsecs = 0
+	} else if parsecs >= len(jumpColors) {
+		parsecs = len(jumpColors) - 1
+	}
+
+	return jumpColors[parsecs]
+}
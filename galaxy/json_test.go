@@ -0,0 +1,128 @@
+package galaxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chewxy/math32"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	g := New(Region{From: Sector{X: -1, Y: 0, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}}, DefaultRules)
+	g.SetNames(Names{g.Stars[3].Key(): "Regina"})
+	var saved bytes.Buffer
+	if err := g.WriteJSON(&saved); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := ReadJSON(bytes.NewReader(saved.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Stars) != len(g.Stars) || len(loaded.Jumps) != len(g.Jumps) {
+		t.Fatalf("loaded %d stars and %d jumps, saved %d and %d",
+			len(loaded.Stars), len(loaded.Jumps), len(g.Stars), len(g.Jumps))
+	}
+	for id, star := range g.Stars {
+		// The offsets within the sector are worked out again from the
+		// position, so may differ in the last bit.
+		got := *loaded.Stars[id]
+		if math32.Abs(got.SX-star.SX)+math32.Abs(got.SY-star.SY)+math32.Abs(got.SZ-star.SZ) > 1e-6 {
+			t.Fatalf("star %d: offset in sector changed", id)
+		}
+		got.SX, got.SY, got.SZ = star.SX, star.SY, star.SZ
+		if !reflect.DeepEqual(got, *star) {
+			t.Fatalf("star %d: loaded %+v, saved %+v", id, got, *star)
+		}
+		if loaded.Name(id) != g.Name(id) {
+			t.Fatalf("star %d: loaded name %q, saved %q", id, loaded.Name(id), g.Name(id))
+		}
+		if *loaded.World(id) != *g.World(id) {
+			t.Fatalf("star %d: world changed", id)
+		}
+		if !reflect.DeepEqual(loaded.System(id), g.System(id)) {
+			t.Fatalf("star %d: system changed", id)
+		}
+		if len(loaded.JumpsByStar[id]) != len(g.JumpsByStar[id]) {
+			t.Fatalf("star %d: %d jumps, saved %d", id, len(loaded.JumpsByStar[id]), len(g.JumpsByStar[id]))
+		}
+	}
+	for id, jump := range g.Jumps {
+		if *loaded.Jumps[id] != *jump {
+			t.Fatalf("jump %d: loaded %+v, saved %+v", id, *loaded.Jumps[id], *jump)
+		}
+	}
+	if loaded.Region() != g.Region() {
+		t.Fatalf("loaded region %v, saved %v", loaded.Region(), g.Region())
+	}
+}
+
+func TestJSONKeepsCampaignApart(t *testing.T) {
+	g := New(Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}}, DefaultRules)
+	generated := *g.World(5)
+	cut := g.Jumps[0]
+	c := NewCampaign()
+	edit, err := ParseUWP("A9A6AA9-F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Star(g.Stars[5].Key()).World = edit.Changes(g.World(5))
+	c.Star(g.Stars[5].Key()).Name = "Capital"
+	c.RemoveJump(g.Stars[cut.S1ID].Key(), g.Stars[cut.S2ID].Key())
+	g.SetCampaign(c)
+
+	var saved bytes.Buffer
+	if err = g.WriteJSON(&saved); err != nil {
+		t.Fatal(err)
+	}
+	var doc galaxyJSON
+	if err = json.Unmarshal(saved.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if *doc.Worlds[5] != generated || doc.Stars[5].Name != g.Stars[5].Name() || len(doc.Jumps) != len(g.Jumps)+1 {
+		t.Fatalf("saved world %s named %q and %d jumps, generated %s and %d", doc.Worlds[5].UWP(),
+			doc.Stars[5].Name, len(doc.Jumps), generated.UWP(), len(g.Jumps)+1)
+	}
+	loaded, err := ReadJSON(bytes.NewReader(saved.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded.World(5) != *g.World(5) || *loaded.Unedited(5) != generated || loaded.Name(5) != "Capital" ||
+		len(loaded.Jumps) != len(g.Jumps) {
+		t.Fatalf("loaded world %s, unedited %s, named %q with %d jumps", loaded.World(5).UWP(),
+			loaded.Unedited(5).UWP(), loaded.Name(5), len(loaded.Jumps))
+	}
+}
+
+func TestReadJSONRejects(t *testing.T) {
+	tests := map[string]string{
+		"future version": `{"version": 99}`,
+		"unknown class":  `{"version": 1, "stars": [{"class": "Q"}]}`,
+		"missing world":  `{"version": 1, "stars": [{"class": "G"}]}`,
+		"unknown zone":   `{"version": 7, "stars": [{"class": "G"}], "worlds": [{"star": 0, "zone": "Blue"}]}`,
+		"bad campaign": `{"version": 8, "stars": [{"class": "G"}], "worlds": [{"star": 0}],
+			"campaign": {"version": 1, "stars": {"Regina": {}}}}`,
+		"unknown companion": `{"version": 3, "stars": [{"class": "G", "companions": [{"class": "Q"}]}],
+			"worlds": [{"star": 0}]}`,
+		"dangling jump": `{"version": 1, "stars": [{"class": "G"}], "worlds": [{"star": 0}],
+			"jumps": [{"from": 0, "to": 1}]}`,
+	}
+	for name, doc := range tests {
+		if _, err := ReadJSON(strings.NewReader(doc)); err == nil {
+			t.Errorf("%s: read without error", name)
+		}
+	}
+}
+
+func TestReadJSONBeforeSubtypes(t *testing.T) {
+	doc := `{"version": 2, "stars": [{"class": "G", "mass": 1.05}], "worlds": [{"star": 0}]}`
+	g, err := ReadJSON(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Stars[0].Spectral(); got != "G9 V" {
+		t.Fatalf("version 2 star is %s, want G9 V", got)
+	}
+}
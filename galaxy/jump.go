@@ -0,0 +1,188 @@
+package galaxy
+
+import (
+	"image/color"
+	"math"
+)
+
+// Jump is a route between two stars. Parsecs is its length in whole parsecs,
+// rounded down, which picks its color, and Distance the exact length in
+// parsecs. Rating is the jump drive rating needed to make it.
+type Jump struct {
+	Color    color.RGBA
+	Parsecs  int
+	Distance float32
+	S1ID     int
+	S2ID     int
+}
+
+const intensityStep = 8
+
+var (
+	intensity = []uint8{
+		0, 0, intensityStep, 2 * intensityStep, 3 * intensityStep, 4 * intensityStep, 5 * intensityStep,
+	}
+	jumpColors = []color.RGBA{
+		{R: math.MaxUint8 - eighth, G: 0, B: 0, A: math.MaxUint8 - intensity[0]},
+		{R: math.MaxUint8 - eighth, G: half + eighth - eighth, B: 0, A: math.MaxUint8 - intensity[1]},
+		{R: math.MaxUint8 - eighth, G: math.MaxUint8 - eighth, B: 0, A: math.MaxUint8 - intensity[2]},
+		{R: 0, G: math.MaxUint8 - eighth, B: 0, A: math.MaxUint8 - intensity[3]},
+		{R: 0, G: 0, B: math.MaxUint8 - eighth, A: math.MaxUint8 - intensity[4]},
+		//{R: math.MaxUint8 - quarter, G: 0, B: math.MaxUint8 - quarter, A: math.MaxUint8 - intensity[5]},//
+	}
+
+	noJump = Jump{Color: color.RGBA{R: 0, G: 0, B: 0, A: 0}, Parsecs: 0, Distance: 20480.0, S1ID: -1, S2ID: -1}
+)
+
+// Neighbour returns the star at the other end of the jump from starID.
+func (j *Jump) Neighbour(starID int) int {
+	if j.S1ID == starID {
+		return j.S2ID
+	}
+
+	return j.S1ID
+}
+
+// buildJumps finds the jumps between the stars and records the short ones in
+// JumpsByStar.
+func (g *Galaxy) buildJumps() {
+	g.setJumps(findJumps(g.Stars, nil))
+}
+
+// shortJump is the length, in parsecs, under which generated jumps are filed
+// in JumpsByStar.
+const shortJump = 3.0
+
+// setJumps records the jumps and files the short ones under both their stars
+// in JumpsByStar. The X-boat network, trade and polities are worked out
+// again when next asked for.
+func (g *Galaxy) setJumps(jumps []*Jump) {
+	g.xboats, g.trade, g.polities, g.around = nil, nil, nil, nil
+	g.Jumps = jumps
+	g.JumpsByStar = make(map[int][]*Jump)
+	for _, jump := range jumps {
+		if jump.Distance < shortJump {
+			g.fileJump(jump)
+		}
+	}
+}
+
+// fileJump files the jump under both its stars in JumpsByStar.
+func (g *Galaxy) fileJump(jump *Jump) {
+	g.JumpsByStar[jump.S1ID] = append(g.JumpsByStar[jump.S1ID], jump)
+	g.JumpsByStar[jump.S2ID] = append(g.JumpsByStar[jump.S2ID], jump)
+}
+
+// findJumps finds the jumps between stars whose IDs are their indexes. When
+// keep is given, only jumps between kept stars are returned, but the others
+// still compete to be among a kept star's closest three.
+func findJumps(stars []*Star, keep []bool) (result []*Jump) {
+	result = make([]*Jump, 0)
+	ix := newStarIndex(stars, maxJumpDistance)
+	for id, star := range stars {
+		if keep != nil && !keep[id] {
+			continue
+		}
+		for _, jump := range checkForJumps(stars, ix, star, id) {
+			if jump.S1ID == jump.S2ID || keep != nil && !keep[jump.S2ID] {
+				continue
+			}
+			result = append(result, jump)
+		}
+	}
+
+	return
+}
+
+// checkForJumps returns the jumps from a star to the higher numbered stars in
+// range, keeping only the closest three when there are more than three. Jumps
+// are symmetric, so checking only higher IDs makes no copies.
+func checkForJumps(stars []*Star, ix *starIndex, star *Star, id int) (result []*Jump) {
+	result = make([]*Jump, 0)
+	for _, innerID := range ix.after(star, id) {
+		jumpColor := checkFor1jump(star, stars[innerID])
+		if jumpColor.Color.A > 0 {
+			result = append(result, jumpColor)
+		}
+	}
+	closest := []*Jump{&noJump, &noJump, &noJump}
+	if len(result) > 3 {
+		for _, nextJump := range result {
+			if nextJump.Distance < closest[0].Distance {
+				closest[2] = closest[1]
+				closest[1] = closest[0]
+				closest[0] = nextJump
+			} else if nextJump.Distance < closest[1].Distance {
+				closest[2] = closest[1]
+				closest[1] = nextJump
+			} else if nextJump.Distance < closest[2].Distance {
+				closest[2] = nextJump
+			}
+		}
+		result = closest
+	}
+
+	return
+}
+
+func checkFor1jump(s1 *Star, s2 *Star) (result *Jump) {
+	jumpLength := distance(s1, s2) * 100 * parsecsPerLightYear
+	delta := int(jumpLength)
+	if delta < len(jumpColors) {
+		if s1.ID != s2.ID {
+			result = &Jump{jumpColors[delta], delta, jumpLength, s1.ID, s2.ID}
+		} else {
+			result = &noJump
+		}
+	} else {
+		result = &noJump
+	}
+	// Return transparent black if there isn't one
+
+	return
+}
+
+// jumpPair names the stars a jump joins, whichever way round.
+type jumpPair [2]int
+
+func pairOf(jump *Jump) jumpPair {
+	if jump.S1ID < jump.S2ID {
+		return jumpPair{jump.S1ID, jump.S2ID}
+	}
+
+	return jumpPair{jump.S2ID, jump.S1ID}
+}
+
+// TraceJumps returns every jump in the network reachable from the star with
+// the given ID, at most 48 jumps beyond the star's own, nearest first. Each
+// round adds the jumps from both ends of the last round's jumps that aren't
+// listed yet.
+func (g *Galaxy) TraceJumps(id int) (visited []*Jump) {
+	explore := g.JumpsByStar[id]
+	visited = append([]*Jump{}, explore...)
+	seen := make(map[jumpPair]bool)
+	for _, jump := range explore {
+		seen[pairOf(jump)] = true
+	}
+	for longest := 0; longest < 48; longest++ {
+		if len(explore) == 0 {
+			break
+		}
+		next := make([]*Jump, 0)
+		for _, start := range explore {
+			for _, end := range []int{start.S1ID, start.S2ID} {
+				for _, jump := range g.JumpsByStar[end] {
+					pair := pairOf(jump)
+					if jump.S1ID != jump.S2ID && !seen[pair] {
+						seen[pair] = true
+						next = append(next, jump)
+					}
+				}
+			}
+		}
+		visited = append(visited, next...)
+		explore = next
+	}
+
+	return
+}
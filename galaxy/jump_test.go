@@ -0,0 +1,195 @@
+package galaxy
+
+import (
+	"fmt"
+	"testing"
+)
+
+// bruteForceJumps is the original jump search, which compares every star
+// with every other star. buildJumps must match it exactly.
+func bruteForceJumps(stars []*Star) (result []*Jump) {
+	result = make([]*Jump, 0)
+	for id, star := range stars {
+		found := make([]*Jump, 0)
+		for innerID, innerStar := range stars {
+			if innerID == id {
+				continue
+			}
+			jump := checkFor1jump(star, innerStar)
+			if jump.Color.A > 0 && jump.S1ID < jump.S2ID {
+				found = append(found, jump)
+			}
+		}
+		closest := []*Jump{&noJump, &noJump, &noJump}
+		if len(found) > 3 {
+			for _, nextJump := range found {
+				if nextJump.Distance < closest[0].Distance {
+					closest[2] = closest[1]
+					closest[1] = closest[0]
+					closest[0] = nextJump
+				} else if nextJump.Distance < closest[1].Distance {
+					closest[2] = closest[1]
+					closest[1] = nextJump
+				} else if nextJump.Distance < closest[2].Distance {
+					closest[2] = nextJump
+				}
+			}
+			found = closest
+		}
+		for _, jump := range found {
+			if jump.S1ID != jump.S2ID {
+				result = append(result, jump)
+			}
+		}
+	}
+
+	return
+}
+
+func cube(sectors int32) Region {
+	return Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: sectors - 1, Y: sectors - 1, Z: sectors - 1}}
+}
+
+func TestBuildJumpsMatchesBruteForce(t *testing.T) {
+	regions := []Region{
+		cube(2),
+		{From: Sector{X: -2, Y: -1, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}},
+	}
+	for _, region := range regions {
+		g := New(region, DefaultRules)
+		want := bruteForceJumps(g.Stars)
+		if len(g.Jumps) != len(want) {
+			t.Fatalf("%v: got %d jumps, want %d", region, len(g.Jumps), len(want))
+		}
+		for i, jump := range g.Jumps {
+			if *jump != *want[i] {
+				t.Fatalf("%v: jump %d is %+v, want %+v", region, i, *jump, *want[i])
+			}
+		}
+	}
+}
+
+// benchmarkJumps times build over cubes of 8, 64 and 512 sectors, or as
+// many of those as sizes allows.
+func benchmarkJumps(b *testing.B, sizes []int32, build func(g *Galaxy)) {
+	for _, sectors := range sizes {
+		g := New(cube(sectors), DefaultRules)
+		b.Run(fmt.Sprintf("%dsectors", sectors*sectors*sectors), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				build(g)
+			}
+		})
+	}
+}
+
+func BenchmarkBuildJumps(b *testing.B) {
+	benchmarkJumps(b, []int32{2, 4, 8}, (*Galaxy).buildJumps)
+}
+
+// BenchmarkBruteForceJumps is the original algorithm, for comparison. It
+// stops at 64 sectors: at 512 it would run for the best part of an hour.
+func BenchmarkBruteForceJumps(b *testing.B) {
+	benchmarkJumps(b, []int32{2, 4}, func(g *Galaxy) {
+		bruteForceJumps(g.Stars)
+	})
+}
+
+// originalTraceJumps is the original jump tracing, which rescans every jump
+// found so far each round. TraceJumps must match it exactly, in order.
+func originalTraceJumps(g *Galaxy, id int) (visited []*Jump) {
+	same := func(a, b *Jump) bool {
+		return a.S1ID == b.S1ID && a.S2ID == b.S2ID || a.S1ID == b.S2ID && a.S2ID == b.S1ID
+	}
+	addVisits := func(base []*Jump, addition []*Jump) (result []*Jump) {
+		result = base
+		for _, nextJump := range addition {
+			already := false
+			for _, baseJump := range base {
+				if same(baseJump, nextJump) {
+					already = true
+					break
+				}
+			}
+			if !already {
+				result = append(result, nextJump)
+			}
+		}
+
+		return
+	}
+	subtractVisits := func(base []*Jump, subtraction []*Jump) (result []*Jump) {
+		result = make([]*Jump, 0)
+		for _, baseJump := range base {
+			if baseJump.S1ID == baseJump.S2ID {
+				continue
+			}
+			add := true
+			for _, nextJump := range subtraction {
+				if nextJump.S1ID != nextJump.S2ID && same(baseJump, nextJump) {
+					add = false
+					break
+				}
+			}
+			if add {
+				result = append(result, baseJump)
+			}
+		}
+
+		return
+	}
+	maybeAppend := func(soFar []*Jump, nextJump *Jump) []*Jump {
+		for _, alreadyJumps := range soFar {
+			if same(nextJump, alreadyJumps) {
+				return soFar
+			}
+		}
+
+		return append(soFar, nextJump)
+	}
+	nextVisits := func(base []*Jump, visited []*Jump) (result []*Jump) {
+		result = make([]*Jump, 0)
+		for _, start := range base {
+			for _, nextJump := range g.JumpsByStar[start.S1ID] {
+				result = maybeAppend(result, nextJump)
+			}
+			for _, nextJump := range g.JumpsByStar[start.S2ID] {
+				result = maybeAppend(result, nextJump)
+			}
+		}
+
+		return subtractVisits(result, visited)
+	}
+
+	explore := g.JumpsByStar[id]
+	visited = explore
+	for longest := 0; longest < 48; longest++ {
+		if len(explore) == 0 {
+			break
+		}
+		explore = nextVisits(explore, visited)
+		visited = addVisits(visited, explore)
+	}
+
+	return
+}
+
+func TestTraceJumpsMatchesOriginal(t *testing.T) {
+	g := New(cube(1), DefaultRules)
+	traced := 0
+	for id := 0; id < len(g.Stars); id += 97 {
+		want := originalTraceJumps(g, id)
+		traced += len(want)
+		got := g.TraceJumps(id)
+		if len(got) != len(want) {
+			t.Fatalf("star %d: traced %d jumps, want %d", id, len(got), len(want))
+		}
+		for i, jump := range got {
+			if jump != want[i] {
+				t.Fatalf("star %d: jump %d is %+v, want %+v", id, i, *jump, *want[i])
+			}
+		}
+	}
+	if traced == 0 {
+		t.Fatal("no jumps traced")
+	}
+}
@@ -0,0 +1,155 @@
+package galaxy
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Seeds for the name dice, "name" and "cult" in ASCII.
+const (
+	nameSeed    = 0x6e616d65
+	cultureSeed = 0x63756c74
+)
+
+// cultureSpan is how many sectors along each axis share a culture, so names
+// sound alike across a few hundred light years and change beyond.
+const cultureSpan = 4
+
+// culture is a naming style: names are built from a couple of syllables,
+// each an onset, a vowel and sometimes a coda, and now and then an ending.
+type culture struct {
+	name    string
+	onsets  []string
+	vowels  []string
+	codas   []string
+	endings []string
+}
+
+var cultures = []culture{
+	{
+		name:    "Anglic",
+		onsets:  []string{"b", "c", "d", "f", "g", "h", "l", "m", "n", "p", "r", "s", "t", "w", "br", "cl", "st", "tr"},
+		vowels:  []string{"a", "e", "i", "o", "u", "a", "e", "ea", "ia", "ou"},
+		codas:   []string{"n", "r", "s", "l", "m", "nd", "th", "x", "rn", "ck"},
+		endings: []string{"ia", "on", "us", "ford", "ton", "ica", "ell"},
+	},
+	{
+		name:    "Vilani",
+		onsets:  []string{"k", "g", "sh", "kh", "l", "m", "n", "d", "z", "s", "dh", "r"},
+		vowels:  []string{"a", "i", "u", "aa", "ii", "e", "a", "i"},
+		codas:   []string{"r", "n", "k", "sh", "m", "g"},
+		endings: []string{"kin", "ur", "ashi", "aar", "gan"},
+	},
+	{
+		name:    "Zhodani",
+		onsets:  []string{"zh", "sh", "ch", "dr", "pl", "vl", "j", "f", "t", "kr", "br", "l", "s"},
+		vowels:  []string{"ie", "ia", "o", "e", "a", "ei", "io"},
+		codas:   []string{"r", "l", "nch", "zh", "ts", "pr", "ns"},
+		endings: []string{"iepr", "al", "ansh", "iashav", "evl"},
+	},
+	{
+		name:    "Aslan",
+		onsets:  []string{"h", "ft", "kh", "s", "w", "y", "r", "k", "tl", "hk"},
+		vowels:  []string{"ea", "ao", "ai", "oi", "iy", "ua", "e", "a", "o"},
+		codas:   []string{"h", "r", "w", "l", "kh", "s", "yh"},
+		endings: []string{"ahk", "tyel", "eakh", "oiw", "rlao"},
+	},
+}
+
+// Culture names the naming style of the stars in a sector, such as
+// "Vilani". Blocks of cultureSpan sectors each way share one.
+func Culture(sector Sector) string {
+	return cultureOf(sector).name
+}
+
+func cultureOf(sector Sector) culture {
+	block := Sector{X: floorDiv(sector.X, cultureSpan), Y: floorDiv(sector.Y, cultureSpan),
+		Z: floorDiv(sector.Z, cultureSpan)}
+
+	return cultures[sectorDice(block, cultureSeed).Intn(len(cultures))]
+}
+
+// floorDiv divides rounding down, so the blocks either side of 0 are the
+// same size.
+func floorDiv(a, b int32) (q int32) {
+	q = a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+
+	return
+}
+
+// Name is the star's generated name, in the style of its sector's culture.
+// It is the same in every region and every run; Galaxy.Name gives the name
+// players see, which may be overridden.
+func (s *Star) Name() string {
+	style := cultureOf(s.Sector)
+	dice := starDice(s, nameSeed)
+	syllables := 2 + dice.Intn(2)
+	var name strings.Builder
+	coda := false
+	for i := 0; i < syllables; i++ {
+		// A syllable after a coda may start with its vowel; otherwise the
+		// vowels would run together.
+		if !coda || dice.Intn(3) > 0 {
+			name.WriteString(style.onsets[dice.Intn(len(style.onsets))])
+		}
+		name.WriteString(style.vowels[dice.Intn(len(style.vowels))])
+		coda = dice.Intn(3) == 0
+		if coda {
+			name.WriteString(style.codas[dice.Intn(len(style.codas))])
+		}
+	}
+	if dice.Intn(4) == 0 {
+		name.WriteString(style.endings[dice.Intn(len(style.endings))])
+	}
+	generated := name.String()
+
+	return strings.ToUpper(generated[:1]) + generated[1:]
+}
+
+// Names are names players have given stars in place of the generated ones,
+// by star Key, so they hold in every region and every run.
+type Names map[string]string
+
+// ReadNames reads names saved before campaigns kept them, a JSON object
+// keyed by star Key; Campaign.ImportNames brings them into a campaign.
+func ReadNames(r io.Reader) (names Names, err error) {
+	names = make(Names)
+	err = json.NewDecoder(r).Decode(&names)
+
+	return
+}
+
+// Name is the star's name: the campaign's or the one players gave it, if
+// any, otherwise the generated one.
+func (g *Galaxy) Name(starID int) string {
+	star := g.Stars[starID]
+	if g.campaign != nil {
+		if edit := g.campaign.Stars[star.Key()]; edit != nil && edit.Name != "" {
+			return edit.Name
+		}
+	}
+	if name, ok := g.names[star.Key()]; ok {
+		return name
+	}
+
+	return star.Name()
+}
+
+// SetNames gives the galaxy's stars the names, keeping the names it already
+// has for other stars. An empty name restores the generated one.
+func (g *Galaxy) SetNames(names Names) {
+	if g.names == nil {
+		g.names = make(Names)
+	}
+	for key, name := range names {
+		if name == "" {
+			delete(g.names, key)
+		} else {
+			g.names[key] = name
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package galaxy
+
+import (
+	"testing"
+)
+
+func TestNames(t *testing.T) {
+	stars := SectorStars(Sector{X: 0, Y: 0, Z: 0}, DefaultRules)
+	seen := make(map[string]bool)
+	for _, star := range stars[:200] {
+		name := star.Name()
+		if name == "" || name != star.Name() {
+			t.Fatalf("star %s named %q, then %q", star.Key(), name, star.Name())
+		}
+		seen[name] = true
+	}
+	if len(seen) < 180 {
+		t.Errorf("200 stars share %d names", len(seen))
+	}
+
+	if Culture(Sector{X: 1, Y: 2, Z: 3}) != Culture(Sector{X: 3, Y: 0, Z: 0}) ||
+		Culture(Sector{X: -1, Y: 0, Z: 0}) != Culture(Sector{X: -4, Y: 0, Z: 0}) {
+		t.Errorf("sectors in one block have different cultures")
+	}
+	found := make(map[string]bool)
+	for x := int32(0); x < 40*cultureSpan; x += cultureSpan {
+		found[Culture(Sector{X: x, Y: 0, Z: 0})] = true
+	}
+	if len(found) != len(cultures) {
+		t.Errorf("40 blocks have %d of %d cultures", len(found), len(cultures))
+	}
+}
+
+func TestNameOverrides(t *testing.T) {
+	g := New(Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}}, DefaultRules)
+	g.SetNames(Names{g.Stars[5].Key(): "Regina"})
+	if g.Name(5) != "Regina" || g.Name(6) != g.Stars[6].Name() {
+		t.Fatalf("named %q and %q", g.Name(5), g.Name(6))
+	}
+
+	g.SetNames(Names{g.Stars[5].Key(): ""})
+	if g.Name(5) != g.Stars[5].Name() {
+		t.Fatalf("cleared name is %q", g.Name(5))
+	}
+}
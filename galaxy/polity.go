@@ -0,0 +1,280 @@
+package galaxy
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+)
+
+// NonAligned is the allegiance code of worlds outside every polity:
+// non-aligned, human dominated.
+const NonAligned = "Na"
+
+// Polity is an interstellar state ruled from a capital world.
+type Polity struct {
+	// Code is the T5 allegiance code, such as "RiEm": two letters of the
+	// capital's name and two of the kind of polity.
+	Code string
+	Name string
+	// Capital is the capital's star ID, or -1 if it lies outside the galaxy,
+	// and CapitalName its generated name.
+	Capital     int
+	CapitalName string
+	// Color tints the polity's stars.
+	Color color.RGBA
+	// Worlds is how many of the galaxy's stars it holds.
+	Worlds int
+}
+
+const (
+	// capitalPopulation and capitalTech are the least population digit and
+	// tech level a capital needs, with an A or B starport.
+	capitalPopulation = 9
+	capitalTech       = 12
+	// capitalSpacing is how close, in parsecs, a greater candidate keeps a
+	// world from being a capital.
+	capitalSpacing = 12
+	// polityJump is the jump rating polities expand with, the X-boats'.
+	polityJump = 4
+	// reachStep is how many parsecs a polity reaches for each point of
+	// polityReach, and maxReach the furthest any reaches. Together with
+	// capitalSpacing they come to less than a sector, so a star's allegiance
+	// is settled by its own sector and those around it.
+	reachStep = 2
+	maxReach  = 18
+	// politySeed seeds the dice for a polity's color and code mark, "poli" in
+	// ASCII.
+	politySeed = 0x706f6c69
+)
+
+// polityKinds name a polity by its capital's government code.
+var polityKinds = []string{"Confederation", "Combine", "Republic", "Oligarchy", "Federation", "Technocracy",
+	"Protectorate", "League", "Directorate", "Commonality", "Empire", "Hegemony", "Alliance", "Theocracy"}
+
+// polityColors tint the polities' stars, each picked by its capital's dice.
+var polityColors = []color.RGBA{
+	{R: 255, G: 96, B: 96, A: 255},
+	{R: 96, G: 160, B: 255, A: 255},
+	{R: 255, G: 208, B: 64, A: 255},
+	{R: 96, G: 224, B: 128, A: 255},
+	{R: 208, G: 112, B: 255, A: 255},
+	{R: 255, G: 144, B: 48, A: 255},
+	{R: 64, G: 224, B: 224, A: 255},
+	{R: 255, G: 112, B: 192, A: 255},
+	{R: 176, G: 224, B: 64, A: 255},
+	{R: 160, G: 128, B: 96, A: 255},
+}
+
+// codeMarks tell apart polities whose allegiance codes would be the same,
+// after the first letter of their kind. Each polity starts from a mark of its
+// own, rolled by its capital's dice.
+const codeMarks = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// polityMap is the polities and which holds each star, by star ID, nil for
+// non-aligned stars.
+type polityMap struct {
+	polities []*Polity
+	byStar   []*Polity
+}
+
+// candidate is a world fit to be a capital.
+type candidate struct {
+	star  *Star
+	world *World
+	// id is the star's ID in the galaxy's surroundings.
+	id    int
+	key   string
+	score int
+}
+
+func newCandidate(star *Star, world *World, id int) *candidate {
+	return &candidate{star: star, world: world, id: id, key: star.Key(), score: world.PopBase + world.TechLevelBase}
+}
+
+// greater orders candidates by population and tech level, and then by key so
+// that no two are equal.
+func (c *candidate) greater(other *candidate) bool {
+	if c.score != other.score {
+		return c.score > other.score
+	}
+
+	return c.key < other.key
+}
+
+// Polities returns the polities holding the galaxy's stars, greatest first.
+// They are worked out the first time they are asked for, and again after the
+// worlds or jumps change.
+func (g *Galaxy) Polities() []*Polity {
+	return g.polityMap().polities
+}
+
+// Allegiance returns the polity holding the star, or nil if it is
+// non-aligned.
+func (g *Galaxy) Allegiance(starID int) *Polity {
+	return g.polityMap().byStar[starID]
+}
+
+// AllegianceCode is the code of the polity holding the star, or NonAligned.
+func (g *Galaxy) AllegianceCode(starID int) string {
+	if polity := g.Allegiance(starID); polity != nil {
+		return polity.Code
+	}
+
+	return NonAligned
+}
+
+func (g *Galaxy) polityMap() *polityMap {
+	if g.polities == nil {
+		g.polities = g.buildPolities()
+	}
+
+	return g.polities
+}
+
+// polityReach is how many parsecs a polity reaches from its capital along its
+// jumps: more for a more populous, more advanced capital.
+func polityReach(capital *World) float32 {
+	reach := float32(capital.PopBase-6+(capital.TechLevelBase-8)/2) * reachStep
+	if reach > maxReach {
+		reach = maxReach
+	}
+
+	return reach
+}
+
+// capitalWorld reports whether the world is fit to be a capital: populous
+// and advanced, with an A or B starport, outside Red zones.
+func capitalWorld(world *World) bool {
+	return world.PopBase >= capitalPopulation && world.TechLevelBase >= capitalTech && world.Zone != RedZone &&
+		(world.StarPort == "A" || world.StarPort == "B")
+}
+
+// parsecsApart is the distance between two stars in parsecs.
+func parsecsApart(s1, s2 *Star) float32 {
+	return distance(s1, s2) * 100 * parsecsPerLightYear
+}
+
+// buildPolities makes every candidate with no greater one within
+// capitalSpacing a capital, and grows each polity out from its capital a
+// polityJump jump at a time, the greater first, taking every star not yet
+// held until its jumps add up to more than its reach. The candidates and
+// jumps are those of the galaxy's surroundings, and candidates are ordered
+// by their own worlds and keys, so a star is held by the same polity in any
+// larger window.
+func (g *Galaxy) buildPolities() (polities *polityMap) {
+	polities = &polityMap{polities: make([]*Polity, 0), byStar: make([]*Polity, len(g.Stars))}
+	around := g.surroundings()
+	candidates := make([]*candidate, 0)
+	for id, world := range around.worlds {
+		if capitalWorld(world) {
+			candidates = append(candidates, newCandidate(around.stars[id], world, id))
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].greater(candidates[j])
+	})
+	capitals := make([]*candidate, 0)
+	for i, candidate := range candidates {
+		ruled := false
+		for _, greater := range candidates[:i] {
+			if parsecsApart(candidate.star, greater.star) < capitalSpacing {
+				ruled = true
+				break
+			}
+		}
+		if !ruled {
+			capitals = append(capitals, candidate)
+		}
+	}
+
+	founded := make([]*Polity, len(capitals))
+	codes := make(map[string]bool)
+	held := make([]int, len(around.stars))
+	for id := range held {
+		held[id] = -1
+	}
+	travelled := make([]float32, len(around.stars))
+	frontiers := make([][]int, len(capitals))
+	for i, capital := range capitals {
+		kind := "Confederation"
+		if capital.world.GovernmentBase < len(polityKinds) {
+			kind = polityKinds[capital.world.GovernmentBase]
+		}
+		name := capital.star.Name()
+		dice := starDice(capital.star, politySeed)
+		founded[i] = &Polity{
+			Name:        fmt.Sprintf("%s %s", name, kind),
+			Capital:     -1,
+			CapitalName: name,
+			Color:       polityColors[dice.Intn(len(polityColors))],
+		}
+		founded[i].Code = allegianceCode(name, kind, dice.Intn(len(codeMarks)), codes)
+		if around.inside(capital.id) {
+			founded[i].Capital = capital.id
+		}
+		held[capital.id] = i
+		frontiers[i] = []int{capital.id}
+	}
+
+	neighbours := around.neighbours(func(jump *Jump) bool {
+		return jump.Rating() <= polityJump
+	})
+	for growing := true; growing; {
+		growing = false
+		for i, capital := range capitals {
+			reach := polityReach(capital.world)
+			next := make([]int, 0)
+			for _, at := range frontiers[i] {
+				for _, jump := range neighbours[at] {
+					star := jump.Neighbour(at)
+					if held[star] >= 0 || travelled[at]+jump.Distance > reach {
+						continue
+					}
+					held[star] = i
+					travelled[star] = travelled[at] + jump.Distance
+					next = append(next, star)
+				}
+			}
+			frontiers[i] = next
+			growing = growing || len(next) > 0
+		}
+	}
+
+	for id := range g.Stars {
+		if held[id] >= 0 {
+			polities.byStar[id] = founded[held[id]]
+			founded[held[id]].Worlds++
+		}
+	}
+	for _, polity := range founded {
+		if polity.Worlds > 0 {
+			polities.polities = append(polities.polities, polity)
+		}
+	}
+	sort.SliceStable(polities.polities, func(i, j int) bool {
+		return polities.polities[i].Worlds > polities.polities[j].Worlds
+	})
+
+	return
+}
+
+// allegianceCode makes a polity's four letter code from its capital's name
+// and its kind. A code another polity has already is marked instead, after
+// the kind's first letter, starting from the polity's own mark and taking
+// the next free one, and should the marks run out the capital's first letter
+// is followed by a number.
+func allegianceCode(capital, kind string, mark int, taken map[string]bool) (code string) {
+	prefix := []rune(capital + "xx")[:2]
+	code = string(prefix) + kind[:2]
+	for i := 0; taken[code]; i++ {
+		if i < len(codeMarks) {
+			at := (mark + i) % len(codeMarks)
+			code = string(prefix) + kind[:1] + codeMarks[at:at+1]
+		} else {
+			code = fmt.Sprintf("%c%03X", prefix[0], i-len(codeMarks))
+		}
+	}
+	taken[code] = true
+
+	return
+}
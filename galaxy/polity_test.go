@@ -0,0 +1,128 @@
+package galaxy
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAllegianceCode(t *testing.T) {
+	taken := make(map[string]bool)
+	tests := []struct {
+		capital, kind string
+		mark          int
+		code          string
+	}{
+		{"Rigel", "Empire", 0, "RiEm"},
+		{"Ridge", "Empire", 5, "RiE7"},
+		// Its own mark is taken, so it takes the next.
+		{"Rift", "Empire", 5, "RiE8"},
+		{"Rigel", "League", 0, "RiLe"},
+		{"Q", "Republic", 0, "QxRe"},
+	}
+	for _, test := range tests {
+		if got := allegianceCode(test.capital, test.kind, test.mark, taken); got != test.code {
+			t.Errorf("%s %s has code %s, want %s", test.capital, test.kind, got, test.code)
+		}
+	}
+
+	// However many collide, codes stay four characters and apart.
+	for i := 0; i < 2*len(codeMarks); i++ {
+		code := allegianceCode(fmt.Sprintf("Ri%d", i), "Empire", i, taken)
+		if len(code) != 4 {
+			t.Fatalf("code %q isn't four characters", code)
+		}
+	}
+	if len(taken) != 5+2*len(codeMarks) {
+		t.Errorf("%d codes for %d polities", len(taken), 5+2*len(codeMarks))
+	}
+}
+
+func TestPolities(t *testing.T) {
+	g := New(cube(2), Rules{Classic: true})
+	polities := g.Polities()
+	if len(polities) == 0 {
+		t.Fatal("no polities")
+	}
+	codes := make(map[string]bool)
+	capital := -1
+	for i, polity := range polities {
+		if codes[polity.Code] {
+			t.Errorf("two polities are %s", polity.Code)
+		}
+		codes[polity.Code] = true
+		if i > 0 && polity.Worlds > polities[i-1].Worlds {
+			t.Errorf("%s is greater than %s before it", polity.Code, polities[i-1].Code)
+		}
+		if got := len(g.StarsOfPolity(polity.Code)); got != polity.Worlds {
+			t.Errorf("%s holds %d stars, want %d", polity.Code, got, polity.Worlds)
+		}
+		if polity.Capital < 0 {
+			continue
+		}
+		if !capitalWorld(g.World(polity.Capital)) {
+			t.Errorf("%s is ruled from %s", polity.Code, g.World(polity.Capital).UWP())
+		}
+		if g.Allegiance(polity.Capital) != polity {
+			t.Errorf("%s doesn't hold its capital", polity.Code)
+		}
+		if capital < 0 {
+			capital = polity.Capital
+		}
+	}
+	if len(g.StarsOfPolity(NonAligned)) == 0 {
+		t.Error("no non-aligned stars")
+	}
+	if capital < 0 {
+		t.Fatal("no capital in the galaxy")
+	}
+
+	// Renaming a capital keeps its polity's name and code, and emptying its
+	// world brings the polity down.
+	code := g.AllegianceCode(capital)
+	c := NewCampaign()
+	c.Star(g.Stars[capital].Key()).Name = "Renamed"
+	g.SetCampaign(c)
+	if got := g.AllegianceCode(capital); got != code {
+		t.Errorf("renamed capital is %s, was %s", got, code)
+	}
+	zero := 0
+	c.Star(g.Stars[capital].Key()).World = &WorldEdit{Population: &zero}
+	g.SetCampaign(c)
+	if polity := g.Allegiance(capital); polity != nil && polity.Capital == capital {
+		t.Errorf("star %d is still %s's capital after it was emptied", capital, polity.Code)
+	}
+}
+
+func TestAllegianceIsStable(t *testing.T) {
+	// Polities grow over the jumps, which only windows pick the same in
+	// every region.
+	cache := NewSectorCache(Rules{Classic: true})
+	small := Window(Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 1, Y: 0, Z: 0}}, cache)
+	windows := []*Galaxy{
+		Window(Region{From: Sector{X: 1, Y: 0, Z: 0}, To: Sector{X: 1, Y: 0, Z: 1}}, cache),
+		Window(Region{From: Sector{X: 1, Y: -1, Z: 0}, To: Sector{X: 1, Y: 0, Z: 0}}, cache),
+		Window(Region{From: Sector{X: 0, Y: 0, Z: -1}, To: Sector{X: 0, Y: 0, Z: 0}}, cache),
+	}
+	held := 0
+	for _, g := range windows {
+		for id, star := range g.Stars {
+			other, ok := small.Find(star.Sector, star.Index)
+			if !ok {
+				continue
+			}
+			// Codes are kept apart within a region, so only the polity must
+			// match.
+			want, got := small.Allegiance(other), g.Allegiance(id)
+			if (want == nil) != (got == nil) || want != nil && (want.Name != got.Name || want.Color != got.Color) {
+				t.Fatalf("star %s is %s in one region and %s in another", star.Key(), small.AllegianceCode(other),
+					g.AllegianceCode(id))
+			}
+			if got != nil {
+				held++
+			}
+		}
+	}
+	if held == 0 {
+		t.Fatal("no held stars compared")
+	}
+}
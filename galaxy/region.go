@@ -0,0 +1,105 @@
+package galaxy
+
+import (
+	"fmt"
+
+	"github.com/chewxy/math32"
+)
+
+// Region is a rectangular block of sectors from one corner sector to the
+// other, inclusive.
+type Region struct {
+	From Sector `json:"from"`
+	To   Sector `json:"to"`
+}
+
+// DefaultRegion is the 2 x 2 x 2 block of sectors galaxy3d shows unless told
+// otherwise.
+var DefaultRegion = Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 1, Y: 1, Z: 1}}
+
+// Validate reports an error if From lies beyond To on any axis.
+func (r Region) Validate() error {
+	if r.From.X > r.To.X || r.From.Y > r.To.Y || r.From.Z > r.To.Z {
+		return fmt.Errorf("sector %d,%d,%d is beyond %d,%d,%d",
+			r.From.X, r.From.Y, r.From.Z, r.To.X, r.To.Y, r.To.Z)
+	}
+
+	return nil
+}
+
+// Sectors lists the sectors in the region, x outermost and z innermost.
+func (r Region) Sectors() (result []Sector) {
+	result = make([]Sector, 0)
+	for x := r.From.X; x <= r.To.X; x++ {
+		for y := r.From.Y; y <= r.To.Y; y++ {
+			for z := r.From.Z; z <= r.To.Z; z++ {
+				result = append(result, Sector{X: x, Y: y, Z: z})
+			}
+		}
+	}
+
+	return
+}
+
+// Center is the position in the middle of the region.
+func (r Region) Center() Position {
+	return Position{
+		X: (float32(r.From.X) + float32(r.To.X) + 1) / 2,
+		Y: (float32(r.From.Y) + float32(r.To.Y) + 1) / 2,
+		Z: (float32(r.From.Z) + float32(r.To.Z) + 1) / 2,
+	}
+}
+
+// Contains reports whether the sector lies in the region.
+func (r Region) Contains(s Sector) bool {
+	return s.X >= r.From.X && s.X <= r.To.X &&
+		s.Y >= r.From.Y && s.Y <= r.To.Y &&
+		s.Z >= r.From.Z && s.Z <= r.To.Z
+}
+
+// Grow returns the region with n more sectors on every side.
+func (r Region) Grow(n int32) Region {
+	return Region{
+		From: Sector{X: r.From.X - n, Y: r.From.Y - n, Z: r.From.Z - n},
+		To:   Sector{X: r.To.X + n, Y: r.To.Y + n, Z: r.To.Z + n},
+	}
+}
+
+// Around returns a region the same size as r with the given sector in its
+// middle, or just below the middle along axes with an even number of sectors.
+func (r Region) Around(center Sector) Region {
+	span := Sector{X: r.To.X - r.From.X, Y: r.To.Y - r.From.Y, Z: r.To.Z - r.From.Z}
+	from := Sector{X: center.X - span.X/2, Y: center.Y - span.Y/2, Z: center.Z - span.Z/2}
+
+	return Region{From: from, To: Sector{X: from.X + span.X, Y: from.Y + span.Y, Z: from.Z + span.Z}}
+}
+
+// extend grows the region just enough to contain the sector.
+func (r Region) extend(s Sector) Region {
+	if s.X < r.From.X {
+		r.From.X = s.X
+	} else if s.X > r.To.X {
+		r.To.X = s.X
+	}
+	if s.Y < r.From.Y {
+		r.From.Y = s.Y
+	} else if s.Y > r.To.Y {
+		r.To.Y = s.Y
+	}
+	if s.Z < r.From.Z {
+		r.From.Z = s.Z
+	} else if s.Z > r.To.Z {
+		r.To.Z = s.Z
+	}
+
+	return r
+}
+
+// SectorOf returns the sector holding a position.
+func SectorOf(p Position) Sector {
+	return Sector{
+		X: int32(math32.Floor(p.X)),
+		Y: int32(math32.Floor(p.Y)),
+		Z: int32(math32.Floor(p.Z)),
+	}
+}
@@ -0,0 +1,138 @@
+package galaxy
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvHeader names the traveler-report.csv columns. Every row has exactly
+// these columns; the jumps share the last one.
+var csvHeader = []string{"Star", "Name", "X", "Y", "Z", "Spectral", "StarPort", "Size (km)", "Atmosphere", "Hydro Percentage",
+	"Population", "Government", "Law Level", "Tech Level", "UWP", "Trade Codes", "Zone", "Bases", "Allegiance",
+	"Companions", "Jumps"}
+
+const (
+	textReportText = "Star %d %s (%s) at (%f, %f, %f): starport %s, size %d km, %s atmosphere, %d%% water, " +
+		"population %d, %s, law level %d, tech level %s, %s zone%s, UWP %s %s\n"
+	textBasesText      = "    bases: %s\n"
+	textPolityText     = "    allegiance: %s (%s)\n"
+	textCompanionsText = "    companions: %s\n"
+	textNoteText       = "    note: %s\n"
+	textSystemText     = "    system, habitable zone at %.2f AU:\n"
+	textBodyText       = "      %s\n"
+)
+
+// WriteCSV writes the given worlds to w as the traveler-report.csv table.
+func (g *Galaxy) WriteCSV(w io.Writer, worlds []*World) error {
+	out := csv.NewWriter(w)
+	err := out.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+	for _, world := range worlds {
+		err = out.Write(g.worldCSV(world))
+		if err != nil {
+			return err
+		}
+	}
+	out.Flush()
+
+	return out.Error()
+}
+
+// WriteText writes the given worlds to w as one line of prose each.
+func (g *Galaxy) WriteText(w io.Writer, worlds []*World) error {
+	for _, world := range worlds {
+		star := g.Stars[world.StarID]
+		_, err := fmt.Fprintf(w, textReportText, world.StarID, g.Name(world.StarID), star.Spectral(), star.X, star.Y, star.Z,
+			world.StarPort, world.Size, world.Atmosphere.Description, world.Hydro, world.Population, world.Government,
+			world.LawBase, world.TechLevel, world.Zone, zoneReason(world), world.UWP(), world.TradeCodeList())
+		if err != nil {
+			return err
+		}
+		if bases := g.BaseList(world.StarID); bases != "" {
+			_, err = fmt.Fprintf(w, textBasesText, bases)
+			if err != nil {
+				return err
+			}
+		}
+		if polity := g.Allegiance(world.StarID); polity != nil {
+			_, err = fmt.Fprintf(w, textPolityText, polity.Name, polity.Code)
+			if err != nil {
+				return err
+			}
+		}
+		if len(star.Companions) > 0 {
+			_, err = fmt.Fprintf(w, textCompanionsText, star.CompanionList())
+			if err != nil {
+				return err
+			}
+		}
+		if note := g.Note(world.StarID); note != "" {
+			_, err = fmt.Fprintf(w, textNoteText, note)
+			if err != nil {
+				return err
+			}
+		}
+		err = writeSystemText(w, g.System(world.StarID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// zoneReason is why the world is in its zone, in brackets, if it isn't
+// Green.
+func zoneReason(world *World) string {
+	if world.ZoneReason == "" {
+		return ""
+	}
+
+	return " (" + world.ZoneReason + ")"
+}
+
+// writeSystemText writes a system's section of the text report, one body to
+// a line.
+func writeSystemText(w io.Writer, system *System) error {
+	_, err := fmt.Fprintf(w, textSystemText, system.HabitableAU)
+	if err != nil {
+		return err
+	}
+	for _, line := range system.Describe() {
+		_, err = fmt.Fprintf(w, textBodyText, line)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// worldCSV is a world's row of the CSV report. Its jumps are listed in one
+// column, separated by semicolons.
+func (g *Galaxy) worldCSV(world *World) []string {
+	fromStarID := world.StarID
+	jumps := make([]string, 0)
+	for _, jump := range g.JumpsByStar[fromStarID] {
+		toStarID := jump.Neighbour(fromStarID)
+		if toStarID > -1 {
+			jumps = append(jumps, fmt.Sprintf("jump to %d %s is %f parsecs", toStarID, g.Name(toStarID), jump.Distance))
+		}
+	}
+	star := g.Stars[fromStarID]
+
+	return []string{strconv.Itoa(fromStarID), g.Name(fromStarID), formatFloat(star.X), formatFloat(star.Y),
+		formatFloat(star.Z), star.Spectral(), world.StarPort, strconv.Itoa(world.Size), world.Atmosphere.Description,
+		strconv.Itoa(world.Hydro), strconv.FormatUint(world.Population, 10), world.Government, world.LawLevel,
+		strconv.Itoa(world.TechLevelBase), world.UWP(), world.TradeCodeList(), string(world.Zone), g.BaseList(fromStarID),
+		g.AllegianceCode(fromStarID), star.CompanionList(), strings.Join(jumps, "; ")}
+}
+
+func formatFloat(f float32) string {
+	return strconv.FormatFloat(float64(f), 'f', 6, 32)
+}
@@ -0,0 +1,174 @@
+package galaxy
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+
+	"github.com/chewxy/math32"
+)
+
+// ErrNoRoute is returned by Route when no route meets the options.
+var ErrNoRoute = errors.New("no route")
+
+// RouteCost is what Route minimises.
+type RouteCost int
+
+const (
+	// FewestJumps prefers the route with fewest jumps, then the shortest.
+	FewestJumps RouteCost = iota
+	// ShortestDistance prefers the shortest route, then the fewest jumps.
+	ShortestDistance
+)
+
+// RouteOptions describe the ship a route is planned for.
+type RouteOptions struct {
+	// MaxJump is the jump drive rating, 1 to 6.
+	MaxJump int
+	Cost    RouteCost
+	// FuelJumps is how many jumps the ship can make on one tank. Stops at
+	// dry worlds, with no starport or an X port and no gas giants, don't
+	// refill it. 0 ignores fuel.
+	FuelJumps int
+}
+
+// Route is a path through the jump network.
+type Route struct {
+	// Stars are the stops from the start to the destination.
+	Stars    []int
+	Jumps    []*Jump
+	Distance float32
+}
+
+// Rating is the jump drive rating needed to make the jump: its length
+// rounded up to whole parsecs.
+func (j *Jump) Rating() int {
+	rating := int(math32.Ceil(j.Distance))
+	if rating < 1 {
+		rating = 1
+	}
+
+	return rating
+}
+
+// Dry reports whether a ship can't refuel at the world: it has no starport,
+// or an X port, and no gas giants to skim.
+func (w *World) Dry() bool {
+	return (w.StarPort == "X" || w.StarPort == "") && w.GasGiants == 0
+}
+
+// Route finds the best route from one star to another over every jump drawn
+// between stars, not only the short ones in JumpsByStar.
+func (g *Galaxy) Route(from, to int, opts RouteOptions) (route *Route, err error) {
+	if opts.MaxJump < 1 || opts.MaxJump > 6 {
+		return nil, fmt.Errorf("jump rating %d should be 1 to 6", opts.MaxJump)
+	}
+	if from < 0 || from >= len(g.Stars) || to < 0 || to >= len(g.Stars) {
+		return nil, fmt.Errorf("no star %d or %d", from, to)
+	}
+	neighbours := make(map[int][]*Jump)
+	for _, jump := range g.Jumps {
+		if jump.Rating() <= opts.MaxJump {
+			neighbours[jump.S1ID] = append(neighbours[jump.S1ID], jump)
+			neighbours[jump.S2ID] = append(neighbours[jump.S2ID], jump)
+		}
+	}
+
+	start := &routeStep{at: routeState{star: from}}
+	best := map[routeState]*routeStep{start.at: start}
+	queue := &routeQueue{cost: opts.Cost}
+	heap.Push(queue, start)
+	for queue.Len() > 0 {
+		step := heap.Pop(queue).(*routeStep)
+		if step.settled || step.superseded {
+			continue
+		}
+		step.settled = true
+		if step.at.star == to {
+			return step.route(), nil
+		}
+		for _, jump := range neighbours[step.at.star] {
+			next := routeState{star: jump.Neighbour(step.at.star)}
+			if opts.FuelJumps > 0 {
+				next.dryJumps = step.at.dryJumps + 1
+				if next.dryJumps > opts.FuelJumps {
+					continue
+				}
+				if next.star != to && !g.World(next.star).Dry() {
+					next.dryJumps = 0
+				}
+			}
+			candidate := &routeStep{at: next, jumps: step.jumps + 1, distance: step.distance + jump.Distance,
+				previous: step, jump: jump}
+			if held, ok := best[next]; ok {
+				if held.settled || !queue.better(candidate, held) {
+					continue
+				}
+				held.superseded = true
+			}
+			best[next] = candidate
+			heap.Push(queue, candidate)
+		}
+	}
+
+	return nil, ErrNoRoute
+}
+
+// routeState is a star reached with a count of jumps since refuelling.
+type routeState struct {
+	star     int
+	dryJumps int
+}
+
+type routeStep struct {
+	at       routeState
+	jumps    int
+	distance float32
+	previous *routeStep
+	jump     *Jump
+	// settled steps are the best way to their state; superseded ones were
+	// beaten before they left the queue.
+	settled    bool
+	superseded bool
+}
+
+func (s *routeStep) route() (route *Route) {
+	route = &Route{Distance: s.distance}
+	for step := s; step != nil; step = step.previous {
+		route.Stars = append([]int{step.at.star}, route.Stars...)
+		if step.jump != nil {
+			route.Jumps = append([]*Jump{step.jump}, route.Jumps...)
+		}
+	}
+
+	return
+}
+
+// routeQueue is a heap of route steps, cheapest first.
+type routeQueue struct {
+	steps []*routeStep
+	cost  RouteCost
+}
+
+func (q *routeQueue) better(a, b *routeStep) bool {
+	if q.cost == ShortestDistance && a.distance != b.distance {
+		return a.distance < b.distance
+	}
+	if a.jumps != b.jumps {
+		return a.jumps < b.jumps
+	}
+
+	return a.distance < b.distance
+}
+
+func (q *routeQueue) Len() int           { return len(q.steps) }
+func (q *routeQueue) Less(i, j int) bool { return q.better(q.steps[i], q.steps[j]) }
+func (q *routeQueue) Swap(i, j int)      { q.steps[i], q.steps[j] = q.steps[j], q.steps[i] }
+func (q *routeQueue) Push(x interface{}) { q.steps = append(q.steps, x.(*routeStep)) }
+
+func (q *routeQueue) Pop() interface{} {
+	last := q.steps[len(q.steps)-1]
+	q.steps = q.steps[:len(q.steps)-1]
+
+	return last
+}
@@ -0,0 +1,65 @@
+package galaxy
+
+import (
+	"reflect"
+	"testing"
+)
+
+// routeNetwork is five stars: 0-1-2-4 is a chain of short jumps, 0-3-4 two
+// long ones, and star 1 is a dry world.
+func routeNetwork() *Galaxy {
+	g := &Galaxy{}
+	for id := 0; id < 5; id++ {
+		g.Stars = append(g.Stars, &Star{ID: id})
+		g.worlds = append(g.worlds, &World{StarID: id, StarPort: "C"})
+	}
+	g.worlds[1].StarPort = "X"
+	g.setJumps([]*Jump{
+		{Distance: 0.9, S1ID: 0, S2ID: 1},
+		{Distance: 0.8, S1ID: 1, S2ID: 2},
+		{Distance: 0.7, S1ID: 2, S2ID: 4},
+		{Distance: 2.5, S1ID: 0, S2ID: 3},
+		{Distance: 2.6, S1ID: 3, S2ID: 4},
+	})
+
+	return g
+}
+
+func TestRoute(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  RouteOptions
+		stars []int
+	}{
+		{"fewest jumps", RouteOptions{MaxJump: 3, Cost: FewestJumps}, []int{0, 3, 4}},
+		{"shortest", RouteOptions{MaxJump: 3, Cost: ShortestDistance}, []int{0, 1, 2, 4}},
+		{"J2 can't make the long jumps", RouteOptions{MaxJump: 2, Cost: FewestJumps}, []int{0, 1, 2, 4}},
+		{"two jumps a tank crosses the dry world", RouteOptions{MaxJump: 1, FuelJumps: 2}, []int{0, 1, 2, 4}},
+		{"one jump a tank avoids it", RouteOptions{MaxJump: 3, Cost: ShortestDistance, FuelJumps: 1}, []int{0, 3, 4}},
+		{"J1 on one tank can't get there", RouteOptions{MaxJump: 1, FuelJumps: 1}, nil},
+	}
+	g := routeNetwork()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route, err := g.Route(0, 4, test.opts)
+			if test.stars == nil {
+				if err != ErrNoRoute {
+					t.Fatalf("found %v, want no route", route)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(route.Stars, test.stars) || len(route.Jumps) != len(test.stars)-1 {
+				t.Fatalf("route %v, want %v", route.Stars, test.stars)
+			}
+		})
+	}
+}
+
+func TestRouteRejectsRating(t *testing.T) {
+	if _, err := routeNetwork().Route(0, 4, RouteOptions{MaxJump: 7}); err == nil {
+		t.Fatal("J7 accepted")
+	}
+}
@@ -0,0 +1,43 @@
+package galaxy
+
+// Rules choose how sectors are generated. The zero value is the current
+// model.
+type Rules struct {
+	// Classic generates stars as the first versions did: main sequence O to
+	// M only, with M dwarfs given class F's ranges, so each sector has
+	// exactly the stars, and worlds, it always had.
+	Classic bool `json:"classic"`
+	// Density names the model in Densities deciding how many stars each
+	// sector holds; empty is uniform.
+	Density string `json:"density"`
+}
+
+// DefaultRules are the rules galaxy3d uses unless told otherwise.
+var DefaultRules = Rules{}
+
+// Validate reports an error if the density model isn't known.
+func (r Rules) Validate() error {
+	_, err := densityOf(r.Density)
+
+	return err
+}
+
+// density is the rules' density model, uniform if it isn't known.
+func (r Rules) density() Density {
+	density, err := densityOf(r.Density)
+	if err != nil {
+		return Uniform
+	}
+
+	return density
+}
+
+// classes lists the classes the rules generate, in the order each sector
+// rolls them.
+func (r Rules) classes() []classDetails {
+	if r.Classic {
+		return starDetailsByClass[:]
+	}
+
+	return extendedClasses
+}
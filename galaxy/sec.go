@@ -0,0 +1,203 @@
+package galaxy
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// secHeader names the T5 Second Survey columns WriteSEC fills in.
+const secHeader = "Hex\tName\tUWP\tBases\tRemarks\tZone\tPBG\tAllegiance\tStars\n"
+
+// Projection flattens the 3D galaxy onto the 2D hex maps of the Second
+// Survey formats. Stars are projected along Axis onto the plane of the other
+// two axes, taken in x, y, z order, so the default "z" looks down from above
+// like the old isometric view. Each sector's face on that plane becomes one
+// Traveller map sector of Columns by Rows hexes: a star's column comes from
+// its offset in its sector along the first remaining axis and its row from
+// the second, counting from hex 0101. Sectors stacked along Axis share a map
+// sector, and when several stars land on one hex only the most populous world
+// is kept; WriteSEC returns the rest.
+type Projection struct {
+	Axis    string `json:"axis"`
+	Columns int    `json:"columns"`
+	Rows    int    `json:"rows"`
+}
+
+// DefaultProjection looks down the z axis onto standard 32 by 40 hex sectors.
+var DefaultProjection = Projection{Axis: "z", Columns: 32, Rows: 40}
+
+// MapSector is a 2D Traveller map sector a projection puts stars in.
+type MapSector struct {
+	X int32
+	Y int32
+}
+
+// Validate reports a projection that can't be written as four digit hexes.
+func (p Projection) Validate() error {
+	switch p.Axis {
+	case "x", "y", "z":
+	default:
+		return fmt.Errorf("projection axis %q should be x, y or z", p.Axis)
+	}
+	if p.Columns < 1 || p.Columns > 99 || p.Rows < 1 || p.Rows > 99 {
+		return fmt.Errorf("projection of %d by %d hexes should be 1 to 99 each way", p.Columns, p.Rows)
+	}
+
+	return nil
+}
+
+// plane returns the star's offsets within its sector along the two axes the
+// projection keeps, and the sector's coordinates along them.
+func (p Projection) plane(star *Star) (a, b float32, sector MapSector) {
+	switch p.Axis {
+	case "x":
+		return star.SY, star.SZ, MapSector{X: star.Sector.Y, Y: star.Sector.Z}
+	case "y":
+		return star.SX, star.SZ, MapSector{X: star.Sector.X, Y: star.Sector.Z}
+	default:
+		return star.SX, star.SY, MapSector{X: star.Sector.X, Y: star.Sector.Y}
+	}
+}
+
+// MapSector returns the map sector the star is projected into.
+func (p Projection) MapSector(star *Star) (sector MapSector) {
+	_, _, sector = p.plane(star)
+
+	return
+}
+
+// Hex returns the star's hex in its map sector, such as "0412".
+func (p Projection) Hex(star *Star) string {
+	a, b, _ := p.plane(star)
+
+	return fmt.Sprintf("%02d%02d", hexIndex(a, p.Columns), hexIndex(b, p.Rows))
+}
+
+func hexIndex(offset float32, hexes int) (index int) {
+	index = int(offset*float32(hexes)) + 1
+	if index < 1 {
+		index = 1
+	} else if index > hexes {
+		index = hexes
+	}
+
+	return
+}
+
+// Split groups worlds by the map sector they are projected into, as each SEC
+// file holds one map sector.
+func (p Projection) Split(g *Galaxy, worlds []*World) (sectors map[MapSector][]*World) {
+	sectors = make(map[MapSector][]*World)
+	for _, world := range worlds {
+		sector := p.MapSector(g.Stars[world.StarID])
+		sectors[sector] = append(sectors[sector], world)
+	}
+
+	return
+}
+
+// WriteSEC writes worlds in the T5 Second Survey tab-delimited format, one
+// line per occupied hex in hex order. The worlds must all project into one
+// map sector; Split separates them. A hex holds one world, so the others
+// projected onto it are left out and returned, in star ID order.
+func (g *Galaxy) WriteSEC(w io.Writer, worlds []*World, p Projection) (dropped []*World, err error) {
+	err = p.Validate()
+	if err != nil {
+		return
+	}
+	if sectors := len(p.Split(g, worlds)); sectors > 1 {
+		err = fmt.Errorf("worlds span %d map sectors, a SEC file holds one", sectors)
+		return
+	}
+	byHex := make(map[string]*World)
+	for _, world := range worlds {
+		hex := p.Hex(g.Stars[world.StarID])
+		held, ok := byHex[hex]
+		if !ok {
+			byHex[hex] = world
+		} else if moreSettled(world, held) {
+			byHex[hex] = world
+			dropped = append(dropped, held)
+		} else {
+			dropped = append(dropped, world)
+		}
+	}
+	sort.Slice(dropped, func(i, j int) bool { return dropped[i].StarID < dropped[j].StarID })
+	hexes := make([]string, 0, len(byHex))
+	for hex := range byHex {
+		hexes = append(hexes, hex)
+	}
+	sort.Strings(hexes)
+
+	_, err = io.WriteString(w, secHeader)
+	if err != nil {
+		return
+	}
+	for _, hex := range hexes {
+		world := byHex[hex]
+		_, err = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", hex, g.Name(world.StarID), world.UWP(),
+			g.secBases(world.StarID), world.TradeCodeList(), world.Zone.Code(), secPBG(world, g.System(world.StarID)),
+			g.AllegianceCode(world.StarID), secStars(g.Stars[world.StarID]))
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// moreSettled breaks ties for a hex: higher population, then lower star ID.
+func moreSettled(world, than *World) bool {
+	if world.Population != than.Population {
+		return world.Population > than.Population
+	}
+
+	return world.StarID < than.StarID
+}
+
+// secBases writes the T5 base codes: N naval, S scout, M military and W way
+// station.
+func (g *Galaxy) secBases(starID int) string {
+	var codes strings.Builder
+	for _, base := range g.Bases(starID) {
+		codes.WriteString(base.Code())
+	}
+
+	return codes.String()
+}
+
+// secPBG writes the population multiplier, planetoid belts and gas giants.
+// The multiplier is the leading digit of the population.
+func secPBG(world *World, system *System) string {
+	multiplier := uint64(0)
+	if world.Population > 0 {
+		multiplier = world.Population
+		for multiplier >= 10 {
+			multiplier /= 10
+		}
+	}
+	gasGiants := world.GasGiants
+	if gasGiants > 9 {
+		gasGiants = 9
+	}
+
+	belts := system.Count(PlanetoidBelt)
+	if belts > 9 {
+		belts = 9
+	}
+
+	return fmt.Sprintf("%d%d%d", multiplier, belts, gasGiants)
+}
+
+// secStars lists the spectral types of the primary and its companions, such
+// as "G2 V M4 V".
+func secStars(star *Star) string {
+	stars := []string{star.Spectral()}
+	for _, companion := range star.Companions {
+		stars = append(stars, companion.Spectral())
+	}
+
+	return strings.Join(stars, " ")
+}
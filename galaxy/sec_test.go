@@ -0,0 +1,46 @@
+package galaxy
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSECHexCollisions(t *testing.T) {
+	g := New(cube(1), DefaultRules)
+	worlds := make([]*World, 0)
+	for _, star := range g.Stars {
+		worlds = append(worlds, g.World(star.ID))
+	}
+	// One hex for the whole sector keeps only its most populous world.
+	p := Projection{Axis: "z", Columns: 1, Rows: 1}
+	var out bytes.Buffer
+	dropped, err := g.WriteSEC(&out, worlds, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 || len(dropped) != len(worlds)-1 {
+		t.Fatalf("%d worlds wrote %d lines and dropped %d", len(worlds), len(lines)-1, len(dropped))
+	}
+	left := make(map[int]bool)
+	for i, world := range dropped {
+		if i > 0 && world.StarID <= dropped[i-1].StarID {
+			t.Fatalf("dropped star %d after star %d", world.StarID, dropped[i-1].StarID)
+		}
+		left[world.StarID] = true
+	}
+	for _, kept := range worlds {
+		if left[kept.StarID] {
+			continue
+		}
+		if !strings.HasPrefix(lines[1], "0101\t"+g.Name(kept.StarID)+"\t") {
+			t.Fatalf("wrote %q, not star %d", lines[1], kept.StarID)
+		}
+		for _, world := range dropped {
+			if moreSettled(world, kept) {
+				t.Fatalf("kept star %d over the more populous star %d", kept.StarID, world.StarID)
+			}
+		}
+	}
+}
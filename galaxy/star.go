@@ -0,0 +1,552 @@
+package galaxy
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"math/rand"
+	"strings"
+
+	"github.com/chewxy/math32"
+	"github.com/spaolacci/murmur3"
+)
+
+type classDetails struct {
+	class       string
+	brightColor color.RGBA
+	medColor    color.RGBA
+	dimColor    color.RGBA
+	odds        float32
+	fudge       float32
+	minMass     float32
+	deltaMass   float32
+	minRadii    float32
+	deltaRadii  float32
+	minLum      float32
+	deltaLum    float32
+	pixels      int32
+	// hideBeyond is the camera distance, in sectors, beyond which stars of
+	// the class aren't drawn; 0 always draws them.
+	hideBeyond float32
+	// multiplicity is the chance a star of the class has a companion.
+	multiplicity float32
+	// luminosity is the luminosity class stars of the class start with,
+	// empty for remnants and brown dwarfs, which have none.
+	luminosity string
+	// giantOdds and subgiantOdds are the chances a star of the class has
+	// evolved off the main sequence, under the extended rules.
+	giantOdds    float32
+	subgiantOdds float32
+}
+
+// Luminosity classes.
+const (
+	Giant        = "III"
+	Subgiant     = "IV"
+	MainSequence = "V"
+)
+
+// Star is a single generated star.
+type Star struct {
+	ID    int
+	Class string
+	// Subtype runs from 0, the hottest of the class, to 9.
+	Subtype int
+	// Luminosity is the luminosity class, such as V for main sequence, or
+	// empty for remnants and brown dwarfs.
+	Luminosity  string
+	BrightColor color.RGBA
+	DimColor    color.RGBA
+	Pixels      int32
+	Mass        float32
+	Radii       float32
+	Luminance   float32
+	// 3D position
+	X float32
+	Y float32
+	Z float32
+	// sector location, 0 <= SX, SY, SZ < 100
+	SX float32
+	SY float32
+	SZ float32
+	// Sector and Index within it identify the star whatever region it was
+	// generated in.
+	Sector Sector
+	Index  int
+	// Companions orbit the star, which is the system's primary.
+	Companions []*Companion
+}
+
+// Key names the star by its sector and index, such as "0,-1,2/17". It is the
+// same in every region and every run.
+func (s *Star) Key() string {
+	return fmt.Sprintf("%d,%d,%d/%d", s.Sector.X, s.Sector.Y, s.Sector.Z, s.Index)
+}
+
+// Sector identifies a 100 light year cube of space. Sector 0,0,0 spans
+// positions 0 to 1 on each axis; coordinates may be negative.
+type Sector struct {
+	X int32 `json:"x"`
+	Y int32 `json:"y"`
+	Z int32 `json:"z"`
+}
+
+// Position is a point in space, measured in sectors.
+type Position struct {
+	X float32
+	Y float32
+	Z float32
+}
+
+var (
+	tween = uint8(sevenEighths)
+	med   = uint8(threeQuarters)
+	dim   = uint8(half)
+
+	classO = classDetails{
+		class:        "O",
+		brightColor:  color.RGBA{R: 0, G: 0, B: tween, A: opaque},
+		medColor:     color.RGBA{R: 0, G: 0, B: tween, A: opaque},
+		dimColor:     color.RGBA{R: 0, G: 0, B: med, A: opaque},
+		odds:         .0000003,
+		fudge:        .0000000402,
+		minMass:      16.00001,
+		deltaMass:    243.2,
+		minRadii:     6,
+		deltaRadii:   17.3,
+		minLum:       30000,
+		deltaLum:     147000.2,
+		pixels:       11,
+		multiplicity: .8,
+		luminosity:   MainSequence,
+		giantOdds:    .3,
+		subgiantOdds: .2,
+	}
+
+	classB = classDetails{
+		class:        "B",
+		brightColor:  color.RGBA{R: dim, G: dim, B: tween, A: opaque},
+		medColor:     color.RGBA{R: dim / two, G: dim / two, B: half, A: opaque},
+		dimColor:     color.RGBA{R: dim / (two * two), G: dim / (two * two), B: tween / two, A: opaque},
+		odds:         .0013,
+		fudge:        .0003,
+		minMass:      2.1,
+		deltaMass:    13.9,
+		minRadii:     1.8,
+		deltaRadii:   4.8,
+		minLum:       25,
+		deltaLum:     29975,
+		pixels:       8,
+		multiplicity: .7,
+		luminosity:   MainSequence,
+		giantOdds:    .1,
+		subgiantOdds: .1,
+	}
+
+	classA = classDetails{
+		class:        "A",
+		brightColor:  color.RGBA{R: tween, G: tween, B: tween, A: opaque},
+		medColor:     color.RGBA{R: sevenEighths, G: sevenEighths, B: sevenEighths, A: opaque},
+		dimColor:     color.RGBA{R: half, G: half, B: half, A: opaque},
+		odds:         .006,
+		fudge:        .0018,
+		minMass:      1.4,
+		deltaMass:    .7,
+		minRadii:     1.4,
+		deltaRadii:   .4,
+		minLum:       5,
+		deltaLum:     20,
+		pixels:       6,
+		multiplicity: .55,
+		luminosity:   MainSequence,
+		giantOdds:    .05,
+		subgiantOdds: .1,
+	}
+
+	classF = classDetails{
+		class:        "F",
+		brightColor:  color.RGBA{R: tween, G: tween, B: sevenEighths, A: opaque},
+		medColor:     color.RGBA{R: sevenEighths, G: sevenEighths, B: half, A: opaque},
+		dimColor:     color.RGBA{R: half, G: half, B: quarter / two, A: opaque},
+		odds:         .03,
+		fudge:        .012,
+		minMass:      1.04,
+		deltaMass:    .36,
+		minRadii:     1.15,
+		deltaRadii:   .25,
+		minLum:       1.5,
+		deltaLum:     3.5,
+		pixels:       5,
+		multiplicity: .5,
+		luminosity:   MainSequence,
+		giantOdds:    .03,
+		subgiantOdds: .08,
+	}
+
+	classG = classDetails{
+		class:        "G",
+		brightColor:  color.RGBA{R: tween, G: tween, B: 0, A: opaque},
+		medColor:     color.RGBA{R: sevenEighths, G: sevenEighths, B: 0, A: opaque},
+		dimColor:     color.RGBA{R: half, G: half, B: 0, A: opaque},
+		odds:         .076,
+		fudge:        .01102,
+		minMass:      .8,
+		deltaMass:    .24,
+		minRadii:     .96,
+		deltaRadii:   .19,
+		minLum:       .6,
+		deltaLum:     .9,
+		pixels:       4,
+		hideBeyond:   4,
+		multiplicity: .45,
+		luminosity:   MainSequence,
+		giantOdds:    .02,
+		subgiantOdds: .06,
+	}
+
+	classK = classDetails{
+		class:        "K",
+		brightColor:  color.RGBA{R: tween, G: tween - eighth, B: tween - quarter, A: opaque},
+		medColor:     color.RGBA{R: threeQuarters, G: threeQuarters - eighth, B: half, A: opaque},
+		dimColor:     color.RGBA{R: half, G: half - eighth, B: quarter, A: opaque},
+		odds:         .121,
+		fudge:        .042,
+		minMass:      .45,
+		deltaMass:    .35,
+		minRadii:     .7,
+		deltaRadii:   .26,
+		minLum:       .08,
+		deltaLum:     .52,
+		pixels:       3,
+		hideBeyond:   2,
+		multiplicity: .35,
+		luminosity:   MainSequence,
+		giantOdds:    .02,
+		subgiantOdds: .04,
+	}
+
+	// classM is the classic M dwarf, which has class F's mass, radius and
+	// luminance ranges. Only the classic rules still use it.
+	classM = classDetails{
+		class:        "M",
+		brightColor:  color.RGBA{R: tween, G: 0, B: 0, A: opaque},
+		medColor:     color.RGBA{R: sevenEighths, G: 0, B: 0, A: opaque},
+		dimColor:     color.RGBA{R: threeQuarters, G: 0, B: 0, A: opaque},
+		odds:         .7645,
+		fudge:        .04,
+		minMass:      1.04,
+		deltaMass:    .36,
+		minRadii:     1.15,
+		deltaRadii:   .25,
+		minLum:       1.5,
+		deltaLum:     3.5,
+		pixels:       2,
+		hideBeyond:   1,
+		multiplicity: .25,
+		luminosity:   MainSequence,
+	}
+
+	// classMDwarf is classM with its real ranges.
+	classMDwarf = classDetails{
+		class:        "M",
+		brightColor:  classM.brightColor,
+		medColor:     classM.medColor,
+		dimColor:     classM.dimColor,
+		odds:         classM.odds,
+		fudge:        classM.fudge,
+		minMass:      .08,
+		deltaMass:    .37,
+		minRadii:     .1,
+		deltaRadii:   .5,
+		minLum:       .0001,
+		deltaLum:     .0799,
+		pixels:       2,
+		hideBeyond:   1,
+		multiplicity: .25,
+		luminosity:   MainSequence,
+	}
+
+	classWhiteDwarf = classDetails{
+		class:        "D",
+		brightColor:  color.RGBA{R: sevenEighths, G: sevenEighths, B: opaque, A: opaque},
+		medColor:     color.RGBA{R: threeQuarters, G: threeQuarters, B: sevenEighths, A: opaque},
+		dimColor:     color.RGBA{R: half, G: half, B: threeQuarters, A: opaque},
+		odds:         .06,
+		fudge:        .01,
+		minMass:      .5,
+		deltaMass:    .4,
+		minRadii:     .008,
+		deltaRadii:   .012,
+		minLum:       .0001,
+		deltaLum:     .03,
+		pixels:       1,
+		hideBeyond:   1,
+		multiplicity: .25,
+	}
+
+	classBrownDwarf = classDetails{
+		class:        "BD",
+		brightColor:  color.RGBA{R: half, G: quarter, B: eighth, A: opaque},
+		medColor:     color.RGBA{R: half - eighth, G: quarter - eighth, B: eighth, A: opaque},
+		dimColor:     color.RGBA{R: quarter, G: eighth, B: eighth / two, A: opaque},
+		odds:         .1,
+		fudge:        .02,
+		minMass:      .013,
+		deltaMass:    .067,
+		minRadii:     .08,
+		deltaRadii:   .04,
+		minLum:       .000001,
+		deltaLum:     .0001,
+		pixels:       1,
+		hideBeyond:   1,
+		multiplicity: .2,
+	}
+
+	classNeutronStar = classDetails{
+		class:        "NS",
+		brightColor:  color.RGBA{R: threeQuarters, G: half, B: opaque, A: opaque},
+		medColor:     color.RGBA{R: half, G: quarter, B: sevenEighths, A: opaque},
+		dimColor:     color.RGBA{R: quarter, G: eighth, B: half, A: opaque},
+		odds:         .0015,
+		fudge:        .001,
+		minMass:      1.1,
+		deltaMass:    .5,
+		minRadii:     .000014,
+		deltaRadii:   .000004,
+		minLum:       .00001,
+		deltaLum:     .001,
+		pixels:       1,
+		multiplicity: .1,
+	}
+
+	classBlackHole = classDetails{
+		class:        "BH",
+		brightColor:  color.RGBA{R: eighth, G: 0, B: quarter, A: opaque},
+		medColor:     color.RGBA{R: eighth / two, G: 0, B: eighth, A: opaque},
+		dimColor:     color.RGBA{R: 0, G: 0, B: eighth / two, A: opaque},
+		odds:         .0008,
+		fudge:        .0006,
+		minMass:      5,
+		deltaMass:    10,
+		minRadii:     .00002,
+		deltaRadii:   .00004,
+		pixels:       1,
+		multiplicity: .1,
+	}
+
+	starDetailsByClass = [7]classDetails{classO, classB, classA, classF, classG, classK, classM}
+
+	// extendedClasses adds remnants and brown dwarfs after the main sequence,
+	// so every sector's main sequence stars keep their places and indexes.
+	extendedClasses = []classDetails{classO, classB, classA, classF, classG, classK, classMDwarf,
+		classWhiteDwarf, classBrownDwarf, classNeutronStar, classBlackHole}
+)
+
+// getStarDetails rolls a sector's stars of one class, density times as many
+// as a sector of average density holds.
+func getStarDetails(classDetails classDetails, sector Sector, density float32, random1m *rand.Rand) []*Star {
+	stars := make([]*Star, 0)
+	loopSize := int32(800 * density * (classDetails.odds - classDetails.fudge + 2*classDetails.fudge*random1m.Float32()))
+	for i := 0; i < int(loopSize); i++ {
+		nextStar := Star{}
+		nextStar.ID = len(stars)
+		random1 := random1m.Float32()
+		nextStar.SX = random1m.Float32()
+		nextStar.SY = random1m.Float32()
+		nextStar.SZ = random1m.Float32()
+		nextStar.X = float32(sector.X) + nextStar.SX
+		nextStar.Y = float32(sector.Y) + nextStar.SY
+		nextStar.Z = float32(sector.Z) + nextStar.SZ
+		nextStar.Class = classDetails.class
+		nextStar.BrightColor = classDetails.brightColor
+		nextStar.DimColor = classDetails.dimColor
+		nextStar.Mass = classDetails.minMass + classDetails.deltaMass*(1+random1)
+		nextStar.Radii = (classDetails.minRadii + random1*classDetails.deltaRadii) / 2
+		nextStar.Luminance = classDetails.minLum + random1*classDetails.deltaLum
+		nextStar.Pixels = classDetails.pixels
+		nextStar.Subtype = subtype(random1)
+		nextStar.Luminosity = classDetails.luminosity
+		stars = append(stars, &nextStar)
+	}
+
+	return stars
+}
+
+// subtype turns the roll that placed a star within its class's ranges into
+// its spectral subtype: the heaviest are 0 and the lightest 9.
+func subtype(roll float32) int {
+	return 9 - int(roll*10)
+}
+
+// SectorStars generates the stars of one sector under the rules, brightest
+// class first, as many as the rules' density model puts there. The same
+// sector always produces the same stars.
+func SectorStars(fromSector Sector, rules Rules) (result []*Star) {
+	result = make([]*Star, 0)
+	random1m := getHash(fromSector)
+	classes := rules.classes()
+	density := rules.density()(fromSector)
+	for _, starDetails := range classes {
+		nextClass := getStarDetails(starDetails, fromSector, density, random1m)
+		result = append(result, nextClass...)
+	}
+	for index, star := range result {
+		star.Sector = fromSector
+		star.Index = index
+		if !rules.Classic {
+			evolve(star)
+		}
+		star.Companions = getCompanions(star, classes)
+	}
+
+	return result
+}
+
+// evolveSeed sets the evolution dice apart from the star's other dice.
+const evolveSeed = 0x65766f6c
+
+// evolve turns some stars of the brighter classes into subgiants or giants,
+// which are several times larger and brighter than on the main sequence.
+func evolve(star *Star) {
+	details, ok := classDetailsOf(star.Class)
+	if !ok || details.giantOdds+details.subgiantOdds == 0 {
+		return
+	}
+	dice := starDice(star, evolveSeed)
+	roll := dice.Float32()
+	switch {
+	case roll < details.giantOdds:
+		star.Luminosity = Giant
+		star.Radii *= 10 + 40*dice.Float32()
+		star.Luminance *= 30 + 70*dice.Float32()
+	case roll < details.giantOdds+details.subgiantOdds:
+		star.Luminosity = Subgiant
+		star.Radii *= 2 + dice.Float32()
+		star.Luminance *= 3 + 2*dice.Float32()
+	}
+}
+
+// Spectral is the star's spectral type, such as "G2 V" or "K0 III", or for
+// remnants and brown dwarfs just the class, such as "D".
+func (s *Star) Spectral() string {
+	return spectral(s.Class, s.Subtype, s.Luminosity)
+}
+
+func spectral(class string, subtype int, luminosity string) string {
+	if luminosity == "" {
+		return class
+	}
+
+	return fmt.Sprintf("%s%d %s", class, subtype, luminosity)
+}
+
+// classDetailsOf looks up a class by its letters.
+func classDetailsOf(class string) (details classDetails, ok bool) {
+	for _, details = range extendedClasses {
+		if details.class == class {
+			return details, true
+		}
+	}
+
+	return classDetails{}, false
+}
+
+// HiddenClasses lists the star classes too dim to draw when the camera is
+// distance sectors from what it is looking at, separated by spaces, such as
+// "K M D BD".
+func HiddenClasses(distance float32) string {
+	hidden := make([]string, 0)
+	for _, details := range extendedClasses {
+		if details.hideBeyond > 0 && distance > details.hideBeyond {
+			hidden = append(hidden, details.class)
+		}
+	}
+
+	return strings.Join(hidden, " ")
+}
+
+// starDice seeds dice for one purpose from a star's sector and index, like
+// worldHash, followed by the purpose.
+func starDice(star *Star, purpose uint32) *rand.Rand {
+	id := murmur3.New64()
+	buf := make([]byte, 20)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(star.Sector.X))
+	binary.LittleEndian.PutUint32(buf[4:], uint32(star.Sector.Y))
+	binary.LittleEndian.PutUint32(buf[8:], uint32(star.Sector.Z))
+	binary.LittleEndian.PutUint32(buf[12:], uint32(star.Index))
+	binary.LittleEndian.PutUint32(buf[16:], purpose)
+	_, err := id.Write(buf)
+	if err != nil {
+		print("Failed to hash star seed")
+	}
+
+	return rand.New(rand.NewSource(int64(id.Sum64())))
+}
+
+// linkDice seeds dice for one purpose from the stars at both ends of a jump,
+// the one travelled from first, so each direction rolls its own.
+func linkDice(from, to *Star, purpose uint32) *rand.Rand {
+	id := murmur3.New64()
+	buf := make([]byte, 36)
+	for i, star := range []*Star{from, to} {
+		binary.LittleEndian.PutUint32(buf[i*16:], uint32(star.Sector.X))
+		binary.LittleEndian.PutUint32(buf[i*16+4:], uint32(star.Sector.Y))
+		binary.LittleEndian.PutUint32(buf[i*16+8:], uint32(star.Sector.Z))
+		binary.LittleEndian.PutUint32(buf[i*16+12:], uint32(star.Index))
+	}
+	binary.LittleEndian.PutUint32(buf[32:], purpose)
+	_, err := id.Write(buf)
+	if err != nil {
+		print("Failed to hash link seed")
+	}
+
+	return rand.New(rand.NewSource(int64(id.Sum64())))
+}
+
+// sectorDice seeds dice for one purpose from a sector's coordinates followed
+// by the purpose, for choices shared by every star in the sector.
+func sectorDice(sector Sector, purpose uint32) *rand.Rand {
+	id := murmur3.New64()
+	buf := make([]byte, 16)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(sector.X))
+	binary.LittleEndian.PutUint32(buf[4:], uint32(sector.Y))
+	binary.LittleEndian.PutUint32(buf[8:], uint32(sector.Z))
+	binary.LittleEndian.PutUint32(buf[12:], purpose)
+	_, err := id.Write(buf)
+	if err != nil {
+		print("Failed to hash sector seed")
+	}
+
+	return rand.New(rand.NewSource(int64(id.Sum64())))
+}
+
+// getHash seeds a sector's dice from its coordinates. Negative coordinates
+// hash as their two's complement bits, so non-negative sectors keep the seeds
+// they had when Sector was unsigned.
+func getHash(aSector Sector) *rand.Rand {
+	id := murmur3.New64()
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, uint32(aSector.X))
+	_, err := id.Write(buf)
+	if err != nil {
+		print("Failed to hash part 1")
+	}
+
+	binary.LittleEndian.PutUint32(buf, uint32(aSector.Y))
+	_, err = id.Write(buf)
+	if err != nil {
+		print("Failed to hash part two")
+	}
+
+	binary.LittleEndian.PutUint32(buf, uint32(aSector.Z))
+	_, err = id.Write(buf)
+	if err != nil {
+		print("Failed to hash part 3")
+	}
+
+	return rand.New(rand.NewSource(int64(id.Sum64())))
+}
+
+func distance(s1 *Star, s2 *Star) float32 {
+	return math32.Sqrt((s1.X-s2.X)*(s1.X-s2.X) + (s1.Y-s2.Y)*(s1.Y-s2.Y) + (s1.Z-s2.Z)*(s1.Z-s2.Z))
+}
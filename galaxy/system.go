@@ -0,0 +1,405 @@
+package galaxy
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/chewxy/math32"
+)
+
+// Kinds of body in a system.
+const (
+	GasGiant      = "Gas giant"
+	PlanetoidBelt = "Planetoid belt"
+	Terrestrial   = "Terrestrial"
+	Satellite     = "Satellite"
+)
+
+// Zones of a system, by how much light an orbit gets.
+const (
+	InnerZone     = "Inner"
+	HabitableZone = "Habitable"
+	OuterZone     = "Outer"
+)
+
+// systemSeed sets the system dice apart from the star's other dice.
+const systemSeed = 0x73797374
+
+// orbitAU are the distances of the orbit slots, nearest first: the classic
+// Traveller table, doubling beyond 10 AU.
+var orbitAU = []float32{0.2, 0.4, 0.7, 1.0, 1.6, 2.8, 5.2, 10, 19.6, 38.8, 77.2, 154, 307.6, 614.8}
+
+// auPerSolarRadius converts a star's radius to AU.
+const auPerSolarRadius = 0.00465
+
+// orbitsDM adjusts the number of orbits by class: dim stars hold fewer and
+// remnants fewer still.
+var orbitsDM = map[string]int{"O": 2, "B": 1, "K": -1, "M": -3, "D": -4, "BD": -6, "NS": -8, "BH": -8}
+
+// Body is a planet, planetoid belt or satellite.
+type Body struct {
+	Kind string `json:"kind"`
+	// Orbit is the slot in orbitAU the body fills, or a satellite's number
+	// around its planet, and AU its distance from the star.
+	Orbit int     `json:"orbit"`
+	AU    float32 `json:"au"`
+	Zone  string  `json:"zone"`
+	// Large marks a large gas giant.
+	Large bool `json:"large,omitempty"`
+	// Size, Atmosphere and Hydro are UWP codes, 0 for gas giants and belts.
+	Size       int     `json:"size"`
+	Atmosphere int     `json:"atmosphere"`
+	Hydro      int     `json:"hydro"`
+	Mainworld  bool    `json:"mainworld,omitempty"`
+	Satellites []*Body `json:"satellites,omitempty"`
+}
+
+// System is the planets and belts around a star, one of which, or one of
+// whose satellites, is the mainworld.
+type System struct {
+	StarID int `json:"star"`
+	// HabitableAU is the middle of the habitable zone, where the star warms
+	// a world as the Sun warms Earth.
+	HabitableAU float32 `json:"habitableAU"`
+	// Bodies are in orbit order, nearest first.
+	Bodies []*Body `json:"bodies"`
+}
+
+// System returns the star's system: as loaded, or else generated around its
+// world. The same star and world always have the same system.
+func (g *Galaxy) System(id int) *System {
+	if g.systems != nil && g.systems[id] != nil {
+		return g.systems[id]
+	}
+
+	return systemFromStar(g.Stars[id], g.World(id))
+}
+
+// Profile is the body's size, atmosphere and hydrographics as UWP digits,
+// such as "786", or LGG or SGG for a large or small gas giant.
+func (b *Body) Profile() string {
+	if b.Kind == GasGiant {
+		if b.Large {
+			return "LGG"
+		}
+		return "SGG"
+	}
+
+	return eHex(b.Size) + eHex(b.Atmosphere) + eHex(b.Hydro)
+}
+
+// Mainworld returns the body that is the mainworld.
+func (s *System) Mainworld() *Body {
+	for _, body := range s.Bodies {
+		if body.Mainworld {
+			return body
+		}
+		for _, satellite := range body.Satellites {
+			if satellite.Mainworld {
+				return satellite
+			}
+		}
+	}
+
+	return nil
+}
+
+// Count is how many bodies of a kind orbit the star, satellites not counted.
+func (s *System) Count(kind string) (count int) {
+	for _, body := range s.Bodies {
+		if body.Kind == kind {
+			count++
+		}
+	}
+
+	return
+}
+
+// systemFromStar lays out a system. It finds which orbits are free, fills
+// them with the world's gas giants, then planetoid belts and terrestrial
+// planets, and gives them satellites. The mainworld, already rolled, is put
+// on the belt, planet or gas giant satellite nearest the habitable zone.
+func systemFromStar(star *Star, world *World) (system *System) {
+	dice := starDice(star, systemSeed)
+	system = &System{StarID: star.ID, HabitableAU: math32.Sqrt(star.Luminance)}
+	free := freeOrbits(star)
+	count := twoD6(dice) + orbitsDM[star.Class]
+	if count < world.GasGiants {
+		count = world.GasGiants
+	}
+	if count > len(free) {
+		count = len(free)
+	}
+	slots := make([]*Body, len(orbitAU))
+
+	// Gas giants take the outermost orbits in use, and belts the orbits just
+	// inside them.
+	used := free[:count]
+	for i := len(used) - 1; i >= len(used)-world.GasGiants && i >= 0; i-- {
+		slots[used[i]] = &Body{Kind: GasGiant, Large: d6(dice) >= 4}
+	}
+	belts := 0
+	if twoD6(dice) >= 8 {
+		belts = d6(dice) - 3
+		if belts < 1 {
+			belts = 1
+		}
+	}
+	for i := len(used) - 1; i >= 0 && belts > 0; i-- {
+		if slots[used[i]] == nil && i+1 < len(used) && slots[used[i+1]] != nil &&
+			slots[used[i+1]].Kind == GasGiant {
+			slots[used[i]] = &Body{Kind: PlanetoidBelt}
+			belts--
+		}
+	}
+	for _, slot := range used {
+		if slots[slot] == nil {
+			if belts > 0 && d6(dice) == 1 {
+				slots[slot] = &Body{Kind: PlanetoidBelt}
+				belts--
+			} else {
+				slots[slot] = &Body{Kind: Terrestrial}
+			}
+		}
+	}
+
+	for slot, body := range slots {
+		if body == nil {
+			continue
+		}
+		body.Orbit = slot
+		body.AU = orbitAU[slot]
+		body.Zone = system.zone(body.AU)
+		system.rollBody(body, dice)
+		system.Bodies = append(system.Bodies, body)
+	}
+	system.placeMainworld(world, free)
+
+	return
+}
+
+// fitGasGiants cuts a rolled world's gas giants down to the orbits its star
+// has free, so its PBG and the refuelling rules agree with its system. A
+// campaign's edit is kept as written.
+func fitGasGiants(star *Star, world *World) {
+	if free := len(freeOrbits(star)); world.GasGiants > free {
+		world.GasGiants = free
+	}
+}
+
+// freeOrbits lists the slots a planet can hold: outside the star and away
+// from companions, whose pull clears orbits from a third to three times
+// their own distance.
+func freeOrbits(star *Star) (free []int) {
+	surface := star.Radii * 2 * auPerSolarRadius
+	for slot, au := range orbitAU {
+		if au < surface*3 {
+			continue
+		}
+		clear := true
+		for _, companion := range star.Companions {
+			if au > companion.Separation/3 && au < companion.Separation*3 {
+				clear = false
+			}
+		}
+		if clear {
+			free = append(free, slot)
+		}
+	}
+
+	return
+}
+
+func (s *System) zone(au float32) string {
+	switch {
+	case au < s.HabitableAU*.75:
+		return InnerZone
+	case au <= s.HabitableAU*1.5:
+		return HabitableZone
+	default:
+		return OuterZone
+	}
+}
+
+// rollBody rolls a planet's size, atmosphere and hydrographics, which depend
+// on its zone, and its satellites.
+func (s *System) rollBody(body *Body, dice *rand.Rand) {
+	moons := 0
+	switch body.Kind {
+	case GasGiant:
+		moons = twoD6(dice)
+		if !body.Large {
+			moons -= 4
+		}
+	case Terrestrial:
+		body.Size = twoD6(dice) - 2
+		if body.Orbit == 0 {
+			body.Size -= 5
+		}
+		if body.Size < 1 {
+			body.Size = 1
+		}
+		s.rollSurface(body, dice)
+		moons = d6(dice) - 3
+	}
+	for moon := 1; moon <= moons; moon++ {
+		satellite := &Body{Kind: Satellite, Orbit: moon, AU: body.AU, Zone: body.Zone}
+		satellite.Size = twoD6(dice) - 6
+		if body.Kind == Terrestrial {
+			satellite.Size = body.Size - 1 - d6(dice)
+		}
+		if satellite.Size < 0 {
+			satellite.Size = 0
+		}
+		s.rollSurface(satellite, dice)
+		body.Satellites = append(body.Satellites, satellite)
+	}
+}
+
+// rollSurface rolls a world's atmosphere and hydrographics. Inner zone
+// worlds are too hot to hold water and outer zone ones have frozen most of
+// their air; small worlds keep neither.
+func (s *System) rollSurface(body *Body, dice *rand.Rand) {
+	body.Atmosphere = twoD6(dice) - 7 + body.Size
+	if body.Zone == OuterZone {
+		body.Atmosphere -= 4
+	}
+	body.Hydro = twoD6(dice) - 7 + body.Size
+	if body.Atmosphere <= 1 || body.Atmosphere >= 10 {
+		body.Hydro -= 4
+	}
+	if body.Zone == InnerZone || body.Size <= 1 {
+		body.Hydro = 0
+	}
+	if body.Size <= 1 {
+		body.Atmosphere = 0
+	}
+	body.Atmosphere = clamp(body.Atmosphere, 0, 15)
+	body.Hydro = clamp(body.Hydro, 0, 10)
+}
+
+func clamp(value, low, high int) int {
+	if value < low {
+		return low
+	}
+	if value > high {
+		return high
+	}
+
+	return value
+}
+
+// placeMainworld makes the body nearest the habitable zone that can hold the
+// mainworld into it: a belt for a size 0 world, otherwise a terrestrial
+// planet or, where a gas giant is nearest, a new satellite of it. If there is
+// none the mainworld gets the free orbit nearest the zone to itself.
+func (s *System) placeMainworld(world *World, free []int) {
+	var best *Body
+	bestDistance := float32(0)
+	for _, body := range s.Bodies {
+		suitable := body.Kind == PlanetoidBelt
+		if world.SizeBase > 0 {
+			suitable = body.Kind == Terrestrial || body.Kind == GasGiant
+		}
+		distance := math32.Abs(math32.Log(body.AU / s.HabitableAU))
+		if suitable && (best == nil || distance < bestDistance) {
+			best, bestDistance = body, distance
+		}
+	}
+
+	if best == nil {
+		best = &Body{Kind: PlanetoidBelt}
+		if world.SizeBase > 0 {
+			best.Kind = Terrestrial
+		}
+		best.Orbit = s.nearestFreeOrbit(free)
+		best.AU = orbitAU[best.Orbit]
+		best.Zone = s.zone(best.AU)
+		s.insert(best)
+	}
+	if best.Kind == GasGiant {
+		satellite := &Body{Kind: Satellite, Orbit: len(best.Satellites) + 1, AU: best.AU, Zone: best.Zone}
+		best.Satellites = append(best.Satellites, satellite)
+		best = satellite
+	}
+	best.Size, best.Atmosphere, best.Hydro = world.SizeBase, world.AtmosphereBase, world.HydroBase
+	best.Mainworld = true
+	if best.Kind == PlanetoidBelt {
+		best.Size, best.Atmosphere, best.Hydro = 0, 0, 0
+	}
+}
+
+// nearestFreeOrbit is the empty slot nearest the habitable zone, from the
+// free ones if any are empty.
+func (s *System) nearestFreeOrbit(free []int) (nearest int) {
+	taken := make(map[int]bool)
+	for _, body := range s.Bodies {
+		taken[body.Orbit] = true
+	}
+	candidates := make([]int, 0)
+	for _, slot := range free {
+		if !taken[slot] {
+			candidates = append(candidates, slot)
+		}
+	}
+	if len(candidates) == 0 {
+		for slot := range orbitAU {
+			if !taken[slot] {
+				candidates = append(candidates, slot)
+			}
+		}
+	}
+	nearest = candidates[0]
+	for _, slot := range candidates {
+		if math32.Abs(math32.Log(orbitAU[slot]/s.HabitableAU)) <
+			math32.Abs(math32.Log(orbitAU[nearest]/s.HabitableAU)) {
+			nearest = slot
+		}
+	}
+
+	return
+}
+
+// insert adds a body in orbit order.
+func (s *System) insert(body *Body) {
+	at := len(s.Bodies)
+	for i, other := range s.Bodies {
+		if other.Orbit > body.Orbit {
+			at = i
+			break
+		}
+	}
+	s.Bodies = append(s.Bodies, nil)
+	copy(s.Bodies[at+1:], s.Bodies[at:])
+	s.Bodies[at] = body
+}
+
+// Describe lists the system's bodies, one per line, each with its satellites,
+// such as "1.0 AU Habitable Terrestrial 786 (mainworld), 1 satellite: 200".
+func (s *System) Describe() (lines []string) {
+	for _, body := range s.Bodies {
+		line := fmt.Sprintf("%.1f AU %s %s %s", body.AU, body.Zone, body.Kind, body.Profile())
+		if body.Mainworld {
+			line += " (mainworld)"
+		}
+		if len(body.Satellites) > 0 {
+			satellites := make([]string, 0)
+			for _, satellite := range body.Satellites {
+				profile := satellite.Profile()
+				if satellite.Mainworld {
+					profile += " (mainworld)"
+				}
+				satellites = append(satellites, profile)
+			}
+			noun := "satellites"
+			if len(satellites) == 1 {
+				noun = "satellite"
+			}
+			line += fmt.Sprintf(", %d %s: %s", len(satellites), noun, strings.Join(satellites, " "))
+		}
+		lines = append(lines, line)
+	}
+
+	return
+}
@@ -0,0 +1,71 @@
+package galaxy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSystems(t *testing.T) {
+	g := New(Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}}, DefaultRules)
+	for id, star := range g.Stars {
+		world := g.World(id)
+		system := g.System(id)
+		if !reflect.DeepEqual(system, g.System(id)) {
+			t.Fatalf("star %s: system changed when generated again", star.Key())
+		}
+		mainworlds := 0
+		for i, body := range system.Bodies {
+			if i > 0 && body.Orbit <= system.Bodies[i-1].Orbit {
+				t.Fatalf("star %s: orbit %d follows orbit %d", star.Key(), body.Orbit, system.Bodies[i-1].Orbit)
+			}
+			if body.Mainworld {
+				mainworlds++
+			}
+			for _, satellite := range body.Satellites {
+				if satellite.Mainworld {
+					mainworlds++
+				}
+			}
+		}
+		if mainworlds != 1 {
+			t.Fatalf("star %s: %d mainworlds", star.Key(), mainworlds)
+		}
+		mainworld := system.Mainworld()
+		if world.SizeBase == 0 && mainworld.Kind != PlanetoidBelt {
+			t.Fatalf("star %s: size 0 mainworld is a %s", star.Key(), mainworld.Kind)
+		}
+		if world.SizeBase > 0 && (mainworld.Size != world.SizeBase || mainworld.Atmosphere != world.AtmosphereBase ||
+			mainworld.Hydro != world.HydroBase) {
+			t.Fatalf("star %s: mainworld %s, world %s", star.Key(), mainworld.Profile(), world.UWP())
+		}
+		if system.Count(GasGiant) != world.GasGiants {
+			t.Fatalf("star %s: %d gas giants, world has %d", star.Key(), system.Count(GasGiant), world.GasGiants)
+		}
+	}
+}
+
+func TestGasGiantsFitOrbits(t *testing.T) {
+	g := New(Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}}, DefaultRules)
+	crowded := -1
+	for id, star := range g.Stars {
+		if free := len(freeOrbits(star)); free < 9 && free > 0 {
+			crowded = id
+			break
+		}
+	}
+	if crowded < 0 {
+		t.Fatal("no star with fewer than 9 free orbits")
+	}
+	star := g.Stars[crowded]
+	nine := 9
+	c := NewCampaign()
+	c.Star(star.Key()).World = &WorldEdit{GasGiants: &nine}
+	g.SetCampaign(c)
+	free := len(freeOrbits(star))
+	if got := g.World(crowded).GasGiants; got != nine {
+		t.Errorf("star %s: edited to %d gas giants, world has %d", star.Key(), nine, got)
+	}
+	if got := g.System(crowded).Count(GasGiant); got != free {
+		t.Errorf("star %s: system has %d gas giants, want %d", star.Key(), got, free)
+	}
+}
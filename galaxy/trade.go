@@ -0,0 +1,224 @@
+package galaxy
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// marketSeed seeds the dice for the tons of goods on sale, "mark" in ASCII.
+const marketSeed = 0x6d61726b
+
+// TradeGood is a line of the speculative trade goods table.
+type TradeGood struct {
+	Name string
+	// BasePrice is the price of a ton in credits, before the broker's
+	// modifiers.
+	BasePrice int
+	// availableAt are the trade codes of the worlds that sell the good;
+	// none means every inhabited world does.
+	availableAt []TradeCode
+	// Tons on sale are tonsDice D6 times tonsTimes.
+	tonsDice  int
+	tonsTimes int
+	// purchaseDMs and saleDMs help a buyer and a seller at worlds with the
+	// trade codes.
+	purchaseDMs map[TradeCode]int
+	saleDMs     map[TradeCode]int
+}
+
+// TradeGoods is the trade goods table, the common goods every inhabited
+// world sells first.
+var TradeGoods = []TradeGood{
+	{"Basic Electronics", 10000, nil, 1, 10,
+		map[TradeCode]int{Industrial: 2, Rich: 1}, map[TradeCode]int{LowPopulation: 2, Poor: 1}},
+	{"Basic Machine Parts", 10000, nil, 1, 10,
+		map[TradeCode]int{Asteroid: 2, Industrial: 2}, map[TradeCode]int{Agricultural: 2, LowPopulation: 3}},
+	{"Basic Manufactured Goods", 10000, nil, 1, 10,
+		map[TradeCode]int{HighPopulation: 2, Industrial: 2}, map[TradeCode]int{LowPopulation: 3, Poor: 1}},
+	{"Basic Raw Materials", 5000, nil, 1, 10,
+		map[TradeCode]int{Agricultural: 3, Desert: 2}, map[TradeCode]int{Industrial: 2, Poor: 2}},
+	{"Basic Consumables", 2000, nil, 1, 10,
+		map[TradeCode]int{Agricultural: 3, WaterWorld: 2}, map[TradeCode]int{Asteroid: 1, Vacuum: 1}},
+	{"Basic Ore", 1000, nil, 1, 10,
+		map[TradeCode]int{Asteroid: 4}, map[TradeCode]int{Industrial: 3, LowPopulation: 1}},
+	{"Advanced Electronics", 100000, []TradeCode{Industrial}, 1, 5,
+		map[TradeCode]int{Industrial: 2}, map[TradeCode]int{Rich: 2, Asteroid: 3}},
+	{"Advanced Machine Parts", 75000, []TradeCode{Industrial}, 1, 5,
+		map[TradeCode]int{Industrial: 2}, map[TradeCode]int{Asteroid: 2, LowPopulation: 1}},
+	{"Crystals & Gems", 20000, []TradeCode{Asteroid, Desert}, 1, 5,
+		map[TradeCode]int{Asteroid: 2, Desert: 1}, map[TradeCode]int{Industrial: 3, Rich: 3}},
+	{"Live Animals", 10000, []TradeCode{Agricultural}, 1, 10,
+		map[TradeCode]int{Agricultural: 2}, map[TradeCode]int{LowPopulation: 3}},
+	{"Luxury Consumables", 20000, []TradeCode{Agricultural, WaterWorld}, 1, 10,
+		map[TradeCode]int{Agricultural: 2, WaterWorld: 1}, map[TradeCode]int{Rich: 2, HighPopulation: 2}},
+	{"Luxury Goods", 200000, []TradeCode{HighPopulation}, 1, 1,
+		map[TradeCode]int{HighPopulation: 1}, map[TradeCode]int{Rich: 4}},
+	{"Medical Supplies", 50000, []TradeCode{HighPopulation}, 1, 5,
+		map[TradeCode]int{HighPopulation: 2}, map[TradeCode]int{Industrial: 2, Poor: 1, Rich: 1}},
+	{"Petrochemicals", 10000, []TradeCode{Desert, WaterWorld}, 1, 10,
+		map[TradeCode]int{Desert: 2}, map[TradeCode]int{Industrial: 2, Agricultural: 1}},
+	{"Pharmaceuticals", 100000, []TradeCode{Asteroid, HighPopulation}, 1, 1,
+		map[TradeCode]int{Asteroid: 2, HighPopulation: 1}, map[TradeCode]int{Rich: 2, LowPopulation: 1}},
+	{"Polymers", 7000, []TradeCode{Industrial}, 1, 10,
+		map[TradeCode]int{Industrial: 1}, map[TradeCode]int{Rich: 2}},
+	{"Precious Metals", 50000, []TradeCode{Asteroid, Desert}, 1, 1,
+		map[TradeCode]int{Asteroid: 3, Desert: 1}, map[TradeCode]int{Rich: 3, Industrial: 2}},
+	{"Radioactives", 1000000, []TradeCode{Asteroid, Desert, LowPopulation}, 1, 1,
+		map[TradeCode]int{Asteroid: 2, LowPopulation: 2}, map[TradeCode]int{Industrial: 3, HighPopulation: 1}},
+	{"Robots", 400000, []TradeCode{Industrial}, 1, 5,
+		map[TradeCode]int{Industrial: 1}, map[TradeCode]int{Agricultural: 2, HighPopulation: 1}},
+	{"Spices", 6000, []TradeCode{Desert, WaterWorld}, 1, 10,
+		map[TradeCode]int{Desert: 2}, map[TradeCode]int{HighPopulation: 2, Rich: 3}},
+	{"Textiles", 3000, []TradeCode{Agricultural}, 1, 20,
+		map[TradeCode]int{Agricultural: 2}, map[TradeCode]int{HighPopulation: 3}},
+	{"Uncommon Ore", 5000, []TradeCode{Asteroid}, 1, 20,
+		map[TradeCode]int{Asteroid: 4}, map[TradeCode]int{Industrial: 3}},
+	{"Uncommon Raw Materials", 20000, []TradeCode{Agricultural, Desert, WaterWorld}, 1, 10,
+		map[TradeCode]int{Agricultural: 2, WaterWorld: 1}, map[TradeCode]int{Industrial: 2}},
+	{"Vehicles", 15000, []TradeCode{Industrial}, 1, 10,
+		map[TradeCode]int{Industrial: 2}, map[TradeCode]int{Asteroid: 2, Rich: 2}},
+	{"Wood", 1000, []TradeCode{Agricultural}, 1, 20,
+		map[TradeCode]int{Agricultural: 6}, map[TradeCode]int{Rich: 2, Industrial: 1}},
+}
+
+// brokerRoll is the 3D6 roll prices are quoted at, its average rounded down.
+const brokerRoll = 10
+
+// purchasePercents and salePercents are the modified price table: the
+// percentage of the base price paid and fetched, by the broker's roll plus
+// DMs from -3 to 25.
+var (
+	purchasePercents = []int{300, 250, 200, 175, 150, 135, 125, 120, 115, 110, 105, 100, 95, 90, 85, 80, 75, 70, 65,
+		60, 55, 50, 45, 40, 35, 30, 25, 20, 15}
+	salePercents = []int{10, 20, 30, 40, 45, 50, 55, 60, 65, 70, 75, 80, 85, 90, 100, 105, 110, 115, 120, 125, 130,
+		135, 140, 150, 160, 175, 200, 250, 300}
+)
+
+// Cargo is a good on sale at a world.
+type Cargo struct {
+	Good *TradeGood
+	Tons int
+	// PurchaseDM is the broker's modifier buying it here, and Price what a
+	// ton costs at brokerRoll.
+	PurchaseDM int
+	Price      int
+}
+
+// Deal is a cargo bought at one world and what it fetches at another.
+type Deal struct {
+	Cargo
+	SaleDM    int
+	SalePrice int
+	// Profit is the sale price less the purchase price, per ton.
+	Profit int
+}
+
+// Available reports whether the world sells the good.
+func (good *TradeGood) Available(w *World) bool {
+	if w.PopBase == 0 {
+		return false
+	}
+	if len(good.availableAt) == 0 {
+		return true
+	}
+	for _, code := range good.availableAt {
+		if w.HasTradeCode(code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PurchaseDM is the modifier buying the good at the world: the best of its
+// purchase DMs less the best of its sale DMs.
+func (good *TradeGood) PurchaseDM(w *World) int {
+	return bestDM(w, good.purchaseDMs) - bestDM(w, good.saleDMs)
+}
+
+// SaleDM is the modifier selling the good at the world: the best of its sale
+// DMs less the best of its purchase DMs.
+func (good *TradeGood) SaleDM(w *World) int {
+	return bestDM(w, good.saleDMs) - bestDM(w, good.purchaseDMs)
+}
+
+func bestDM(w *World, dms map[TradeCode]int) (best int) {
+	for code, dm := range dms {
+		if dm > best && w.HasTradeCode(code) {
+			best = dm
+		}
+	}
+
+	return
+}
+
+// PurchasePrice is what a ton of the good costs with the modifier.
+func (good *TradeGood) PurchasePrice(dm int) int {
+	return good.BasePrice * priceTable(purchasePercents, dm) / 100
+}
+
+// SalePrice is what a ton of the good fetches with the modifier.
+func (good *TradeGood) SalePrice(dm int) int {
+	return good.BasePrice * priceTable(salePercents, dm) / 100
+}
+
+// priceTable looks up brokerRoll plus the modifier, clamped to the table.
+func priceTable(percents []int, dm int) (percent int) {
+	row := brokerRoll + dm + 3
+	if row < 0 {
+		row = 0
+	} else if row >= len(percents) {
+		row = len(percents) - 1
+	}
+	percent = percents[row]
+
+	return
+}
+
+// Market lists the goods on sale at the star's world, in the order of
+// TradeGoods. The tons are rolled from the star, so they are the same every
+// time it is asked.
+func (g *Galaxy) Market(starID int) (market []Cargo) {
+	world := g.World(starID)
+	dice := starDice(g.Stars[starID], marketSeed)
+	market = make([]Cargo, 0)
+	for id := range TradeGoods {
+		good := &TradeGoods[id]
+		// Every good rolls, on sale or not, so changing a world's trade
+		// codes leaves the other goods' tons alone.
+		tons := rollDice(dice, good.tonsDice) * good.tonsTimes
+		if !good.Available(world) {
+			continue
+		}
+		dm := good.PurchaseDM(world)
+		market = append(market, Cargo{Good: good, Tons: tons, PurchaseDM: dm, Price: good.PurchasePrice(dm)})
+	}
+
+	return
+}
+
+// Speculation lists what the goods on sale at one star fetch at another,
+// most profitable first.
+func (g *Galaxy) Speculation(from, to int) (deals []Deal) {
+	destination := g.World(to)
+	deals = make([]Deal, 0)
+	for _, cargo := range g.Market(from) {
+		dm := cargo.Good.SaleDM(destination)
+		price := cargo.Good.SalePrice(dm)
+		deals = append(deals, Deal{Cargo: cargo, SaleDM: dm, SalePrice: price, Profit: price - cargo.Price})
+	}
+	sort.SliceStable(deals, func(i, j int) bool {
+		return deals[i].Profit > deals[j].Profit
+	})
+
+	return
+}
+
+// rollDice rolls and totals some D6.
+func rollDice(dice *rand.Rand, count int) (total int) {
+	for i := 0; i < count; i++ {
+		total += d6(dice)
+	}
+
+	return
+}
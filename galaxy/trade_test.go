@@ -0,0 +1,76 @@
+package galaxy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarket(t *testing.T) {
+	tests := []struct {
+		uwp   string
+		goods int
+	}{
+		// Uninhabited: nothing on sale.
+		{"B468010-A", 0},
+		// No trade codes: the six common goods.
+		{"A385443-C", 6},
+		// Hi and In: the common goods, Advanced Electronics, Advanced
+		// Machine Parts, Luxury Goods, Medical Supplies, Pharmaceuticals,
+		// Polymers, Robots and Vehicles.
+		{"A877A00-C", 14},
+	}
+	g := New(cube(1), Rules{Classic: true})
+	for _, test := range tests {
+		edit, err := ParseUWP(test.uwp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := NewCampaign()
+		c.Star(g.Stars[0].Key()).World = &edit
+		g.SetCampaign(c)
+		market := g.Market(0)
+		if len(market) != test.goods {
+			t.Errorf("%s sells %d goods, want %d", test.uwp, len(market), test.goods)
+		}
+		for _, cargo := range market {
+			if cargo.Tons < cargo.Good.tonsTimes || cargo.Price <= 0 {
+				t.Errorf("%s sells %d tons of %s at %d", test.uwp, cargo.Tons, cargo.Good.Name, cargo.Price)
+			}
+		}
+		if !reflect.DeepEqual(market, g.Market(0)) {
+			t.Errorf("%s market changes between calls", test.uwp)
+		}
+	}
+}
+
+func TestPrices(t *testing.T) {
+	wood := &TradeGoods[len(TradeGoods)-1]
+	// Wood is cheapest on agricultural worlds and dearest on rich ones.
+	ag := &World{AtmosphereBase: 6, HydroBase: 6, PopBase: 6, GovernmentBase: 1}
+	ri := &World{AtmosphereBase: 6, HydroBase: 2, PopBase: 8, GovernmentBase: 4}
+	if !wood.Available(ag) || wood.Available(ri) {
+		t.Errorf("wood should be sold on %s, not %s", ag.TradeCodeList(), ri.TradeCodeList())
+	}
+	bought, sold := wood.PurchasePrice(wood.PurchaseDM(ag)), wood.SalePrice(wood.SaleDM(ri))
+	if bought >= wood.BasePrice || sold <= wood.BasePrice {
+		t.Errorf("wood bought for %d on %s and sold for %d on %s", bought, ag.TradeCodeList(), sold,
+			ri.TradeCodeList())
+	}
+	if wood.PurchasePrice(-100) != 3*wood.BasePrice || wood.SalePrice(100) != 3*wood.BasePrice {
+		t.Error("prices should be clamped to the table")
+	}
+}
+
+func TestSpeculation(t *testing.T) {
+	g := New(cube(1), Rules{Classic: true})
+	for from := range g.Stars[:50] {
+		for _, jump := range g.JumpsByStar[from] {
+			deals := g.Speculation(from, jump.Neighbour(from))
+			for id, deal := range deals {
+				if deal.Profit != deal.SalePrice-deal.Price || id > 0 && deal.Profit > deals[id-1].Profit {
+					t.Fatalf("deals from %d to %d out of order: %+v", from, jump.Neighbour(from), deals)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package galaxy
+
+import (
+	"image/color"
+	"math"
+)
+
+// TradeRoute classifies a jump by the trade it carries.
+type TradeRoute string
+
+// The classes of trade route, from busiest down. Jumps carrying less trade
+// than a minor route are NoRoute.
+const (
+	MajorRoute        TradeRoute = "Major"
+	IntermediateRoute TradeRoute = "Intermediate"
+	MinorRoute        TradeRoute = "Minor"
+	NoRoute           TradeRoute = ""
+)
+
+// TradeRoutes lists the classes of trade route, busiest first.
+var TradeRoutes = []TradeRoute{MajorRoute, IntermediateRoute, MinorRoute}
+
+// routeBTNs are the trade, as a bilateral trade number, that makes a jump
+// each class of route.
+var routeBTNs = map[TradeRoute]float64{MajorRoute: 10, IntermediateRoute: 9, MinorRoute: 8}
+
+// routeColors are the trade routes' colors, replacing the jump's own.
+var routeColors = map[TradeRoute]color.RGBA{
+	MajorRoute:        {R: 255, G: 255, B: 255, A: 255},
+	IntermediateRoute: {R: 128, G: 192, B: 255, A: 240},
+	MinorRoute:        {R: 64, G: 128, B: 224, A: 224},
+}
+
+const (
+	// traderWTN is the World Trade Number a world needs to trade beyond its
+	// neighbours.
+	traderWTN = 3
+	// tradeJump is the jump rating of the ships trade routes are run with,
+	// and tradeReach the most jumps a trade goes.
+	tradeJump  = 2
+	tradeReach = 9
+	// minBTN is the least bilateral trade number that is routed.
+	minBTN = 7
+)
+
+// tradeTechDMs modify a world's trade number by tech level, from TL 0 up.
+var tradeTechDMs = []float64{-1, -1, -0.5, -0.5, -0.5, 0, 0, 0, 0, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 1}
+
+// tradePortDMs modify a world's trade number by starport.
+var tradePortDMs = map[string]float64{"A": 1, "B": 0.5, "C": 0, "D": -0.5, "E": -1, "X": -1.5}
+
+// WTN is the world's World Trade Number, a simplified form of the GURPS Far
+// Trader one: half its population digit, adjusted by tech level and
+// starport. Each point is about ten times the trade of the point below.
+func (w *World) WTN() (wtn float64) {
+	tl := w.TechLevelBase
+	if tl >= len(tradeTechDMs) {
+		tl = len(tradeTechDMs) - 1
+	}
+	wtn = float64(w.PopBase)/2 + tradeTechDMs[tl] + tradePortDMs[w.StarPort]
+	if wtn < 0 || w.PopBase == 0 {
+		wtn = 0
+	}
+
+	return
+}
+
+// BTN is the Bilateral Trade Number between two worlds the given number of
+// jumps apart: the sum of their WTNs and a half for each pairing of an
+// agricultural world with a populous or industrial one and of a rich world
+// with an industrial one, at most 5 over the smaller WTN, less a half for
+// each Amber zone and more as the jumps grow. Red zones don't trade.
+func BTN(a, b *World, jumps int) (btn float64) {
+	if a.Zone == RedZone || b.Zone == RedZone || a.PopBase == 0 || b.PopBase == 0 {
+		return 0
+	}
+	btn = a.WTN() + b.WTN()
+	complements := func(x, y *World) (bonus float64) {
+		if x.HasTradeCode(Agricultural) && (y.HasTradeCode(HighPopulation) || y.HasTradeCode(Industrial)) {
+			bonus += 0.5
+		}
+		if x.HasTradeCode(Rich) && y.HasTradeCode(Industrial) {
+			bonus += 0.5
+		}
+
+		return
+	}
+	btn += complements(a, b) + complements(b, a)
+	if most := math.Min(a.WTN(), b.WTN()) + 5; btn > most {
+		btn = most
+	}
+	for _, w := range []*World{a, b} {
+		if w.Zone == AmberZone {
+			btn -= 0.5
+		}
+	}
+	btn -= distanceBTN(jumps)
+
+	return
+}
+
+// distanceBTN is what the jumps between two worlds take off their trade.
+func distanceBTN(jumps int) float64 {
+	switch {
+	case jumps <= 1:
+		return 0
+	case jumps == 2:
+		return 0.5
+	case jumps <= 5:
+		return 1
+	case jumps <= 9:
+		return 1.5
+	case jumps <= 19:
+		return 2
+	default:
+		return 2.5
+	}
+}
+
+// tradeNetwork is the trade carried over each jump, as a bilateral trade
+// number.
+type tradeNetwork map[*Jump]float64
+
+// TradeVolume is the trade the jump carries, as a bilateral trade number:
+// the trade of every pair of worlds routed over it added up. It is 0 for a
+// jump no trade is routed over.
+func (g *Galaxy) TradeVolume(jump *Jump) float64 {
+	return g.tradeNetwork()[jump]
+}
+
+// TradeRoute classifies the jump by the trade it carries.
+func (g *Galaxy) TradeRoute(jump *Jump) TradeRoute {
+	volume := g.TradeVolume(jump)
+	for _, route := range TradeRoutes {
+		if volume >= routeBTNs[route] {
+			return route
+		}
+	}
+
+	return NoRoute
+}
+
+// JumpColor is the color to draw the jump: its trade route's, or its own if
+// it isn't one.
+func (g *Galaxy) JumpColor(jump *Jump) color.RGBA {
+	if c, ok := routeColors[g.TradeRoute(jump)]; ok {
+		return c
+	}
+
+	return jump.Color
+}
+
+// TradeRouteJumps returns the jumps that are trade routes of the class.
+func (g *Galaxy) TradeRouteJumps(route TradeRoute) (jumps []*Jump) {
+	jumps = make([]*Jump, 0)
+	for _, jump := range g.Jumps {
+		if g.TradeRoute(jump) == route {
+			jumps = append(jumps, jump)
+		}
+	}
+
+	return
+}
+
+func (g *Galaxy) tradeNetwork() tradeNetwork {
+	if g.trade == nil {
+		g.trade = g.buildTrade()
+	}
+
+	return g.trade
+}
+
+// buildTrade routes the trade between every pair of trading worlds, those
+// with traderWTN, over the fewest tradeJump jumps between them, never
+// through a Red zone, and adds it up on each jump. Trade between neighbours
+// is routed whatever their WTNs. The worlds of the galaxy's surroundings
+// trade too, those near enough to route over its jumps, and ties are broken
+// by star Key, so a window's jumps carry what they do in any larger window.
+func (g *Galaxy) buildTrade() (network tradeNetwork) {
+	network = make(tradeNetwork)
+	around := g.surroundings()
+	neighbours := around.neighbours(func(jump *Jump) bool {
+		return jump.Rating() <= tradeJump && around.worlds[jump.S1ID].Zone != RedZone &&
+			around.worlds[jump.S2ID].Zone != RedZone
+	})
+
+	// Each point of BTN is ten times the trade, so trade adds up as powers
+	// of ten.
+	trade := make(map[*Jump]float64)
+	for from, source := range around.worlds {
+		if source.PopBase == 0 {
+			continue
+		}
+		reach := tradeReach
+		if source.WTN() < traderWTN {
+			reach = 1
+		}
+		if !around.near(from, float32(reach*tradeJump)) {
+			continue
+		}
+		reachedBy := map[int]*Jump{from: nil}
+		frontier := []int{from}
+		for hops := 1; hops <= reach && len(frontier) > 0; hops++ {
+			next := make([]int, 0)
+			for _, at := range frontier {
+				for _, jump := range neighbours[at] {
+					star := jump.Neighbour(at)
+					if _, seen := reachedBy[star]; seen {
+						continue
+					}
+					reachedBy[star] = jump
+					next = append(next, star)
+					// Each pair is routed once, from the lower key, unless
+					// only the other can reach this far.
+					destination := around.worlds[star]
+					if around.keys[star] < around.keys[from] && (hops == 1 || destination.WTN() >= traderWTN) {
+						continue
+					}
+					btn := BTN(source, destination, hops)
+					if btn < minBTN {
+						continue
+					}
+					path, _ := tracePath(from, star, reachedBy)
+					for _, jump := range path {
+						if around.inside(jump.S1ID) && around.inside(jump.S2ID) {
+							trade[jump] += math.Pow(10, btn)
+						}
+					}
+				}
+			}
+			frontier = next
+		}
+	}
+	for jump, volume := range trade {
+		network[jump] = math.Log10(volume)
+	}
+
+	return
+}
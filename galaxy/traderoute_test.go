@@ -0,0 +1,102 @@
+package galaxy
+
+import (
+	"testing"
+)
+
+func TestTradeNumbers(t *testing.T) {
+	world := func(uwp string, zone Zone) *World {
+		edit, err := ParseUWP(uwp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		edit.Zone = &zone
+
+		return edit.apply(&World{})
+	}
+	// Population 10, TL 12, port A: 5 + 0.5 + 1.
+	hub := world("A877A00-C", GreenZone)
+	// Population 6, TL 7, port C, Ag: 3.
+	farm := world("C566644-7", GreenZone)
+	if hub.WTN() != 6.5 || farm.WTN() != 3 {
+		t.Fatalf("WTNs %.1f and %.1f, want 6.5 and 3", hub.WTN(), farm.WTN())
+	}
+	// 6.5 + 3, a half for Ag with Hi and a half for Ag with In.
+	if btn := BTN(hub, farm, 1); btn != 8 {
+		t.Errorf("neighbouring BTN %.1f, want 8 (the farm's 3 + 5)", btn)
+	}
+	if near, far := BTN(hub, hub, 1), BTN(hub, hub, 6); near != 11.5 || far != 11.5-1.5 {
+		t.Errorf("BTN %.1f at 1 jump and %.1f at 6, want 11.5 and 10", near, far)
+	}
+	if btn := BTN(hub, world("A877A00-C", RedZone), 1); btn != 0 {
+		t.Errorf("Red zone trades at BTN %.1f", btn)
+	}
+	if btn := BTN(hub, world("A877A00-C", AmberZone), 1); btn != 11 {
+		t.Errorf("Amber zone trades at BTN %.1f, want 11", btn)
+	}
+}
+
+func TestTradeRoutes(t *testing.T) {
+	g := New(cube(2), Rules{Classic: true})
+	counts := make(map[TradeRoute]int)
+	for _, jump := range g.Jumps {
+		route := g.TradeRoute(jump)
+		counts[route]++
+		if route == NoRoute {
+			continue
+		}
+		if jump.Rating() > tradeJump {
+			t.Errorf("%s route over a J%d jump", route, jump.Rating())
+		}
+		if g.World(jump.S1ID).Zone == RedZone || g.World(jump.S2ID).Zone == RedZone {
+			t.Errorf("%s route to a Red zone", route)
+		}
+		if g.JumpColor(jump) != routeColors[route] {
+			t.Errorf("%s route colored %v", route, g.JumpColor(jump))
+		}
+	}
+	if counts[MajorRoute] == 0 || counts[MajorRoute] > counts[IntermediateRoute] ||
+		counts[IntermediateRoute] > counts[MinorRoute] || counts[MinorRoute] > counts[NoRoute]/10 {
+		t.Errorf("route counts %v", counts)
+	}
+
+	// Closing a world ends the trade through it.
+	major := g.TradeRouteJumps(MajorRoute)[0]
+	red := RedZone
+	c := NewCampaign()
+	c.Star(g.Stars[major.S1ID].Key()).World = &WorldEdit{Zone: &red}
+	g.SetCampaign(c)
+	if g.TradeRoute(major) != NoRoute {
+		t.Errorf("trade still runs to star %d after it was made a Red zone", major.S1ID)
+	}
+}
+
+func TestTradeRoutesAreStable(t *testing.T) {
+	cache := NewSectorCache(Rules{Classic: true})
+	large := Window(cube(2), cache)
+	small := Window(Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}}, cache)
+	routes := make(map[string]TradeRoute)
+	for _, jump := range large.Jumps {
+		s1, s2 := large.Stars[jump.S1ID], large.Stars[jump.S2ID]
+		if s1.Sector == small.Region().From && s2.Sector == small.Region().From {
+			routes[s1.Key()+" "+s2.Key()] = large.TradeRoute(jump)
+		}
+	}
+	compared := 0
+	for _, jump := range small.Jumps {
+		key := small.Stars[jump.S1ID].Key() + " " + small.Stars[jump.S2ID].Key()
+		want, ok := routes[key]
+		if !ok {
+			t.Fatalf("jump %s is missing from the larger region", key)
+		}
+		if got := small.TradeRoute(jump); got != want {
+			t.Errorf("jump %s is a %q route in one region and a %q one in another", key, got, want)
+		}
+		if want != NoRoute {
+			compared++
+		}
+	}
+	if compared == 0 {
+		t.Fatal("no trade routes compared")
+	}
+}
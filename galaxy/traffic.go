@@ -0,0 +1,228 @@
+package galaxy
+
+import (
+	"math/rand"
+)
+
+// Seeds for the freight, passenger and mail dice of a jump, "frei", "pass"
+// and "mail" in ASCII.
+const (
+	freightSeed   = 0x66726569
+	passengerSeed = 0x70617373
+	mailSeed      = 0x6d61696c
+)
+
+// FreightLot is a consignment a ship can carry for the freight rate.
+type FreightLot struct {
+	Kind string
+	Tons int
+}
+
+// The kinds of freight lot, with the modifier on their traffic roll and the
+// D6 times the tons of each lot.
+var freightKinds = []struct {
+	kind      string
+	dm        int
+	tonsTimes int
+}{
+	{"Major", -4, 10},
+	{"Minor", 0, 5},
+	{"Incidental", 2, 1},
+}
+
+// Passage is a class of passage, with the modifier on its traffic roll.
+type Passage struct {
+	Name string
+	dm   int
+	// Fares are the tickets' prices in credits, by parsecs from 1 to 6.
+	Fares []int
+}
+
+// Passages are the classes of passage, dearest first.
+var Passages = []Passage{
+	{"High", -4, []int{9000, 14000, 21000, 34000, 60000, 210000}},
+	{"Middle", 0, []int{6500, 10000, 14000, 23000, 40000, 130000}},
+	{"Basic", 0, []int{2000, 3000, 5000, 8000, 14000, 55000}},
+	{"Low", 1, []int{700, 1300, 2200, 3900, 7200, 27000}},
+}
+
+// freightRates are the credits a ton of freight pays, by parsecs from 1 to 6.
+var freightRates = []int{1000, 1600, 2600, 4400, 8500, 32000}
+
+// trafficDice is how many D6 of lots or passengers a traffic roll gives, by
+// the roll from 1 up; less gives none and more gives the last.
+var trafficDice = []int{0, 1, 1, 2, 2, 3, 3, 3, 4, 4, 4, 5, 5, 5, 6, 6, 7, 8, 9, 10}
+
+const (
+	// mailTons is the size of a mail container and mailPay what it pays.
+	mailTons = 5
+	mailPay  = 25000
+	// mailRoll is what 2D6 and the modifiers must make for the mail.
+	mailRoll = 12
+)
+
+// Traffic is the freight, passengers and mail waiting at a world for one of
+// its jumps.
+type Traffic struct {
+	From, To int
+	// Parsecs is the jump's rating, which sets the fares and freight rate.
+	Parsecs     int
+	Freight     []FreightLot
+	FreightRate int
+	// Passengers are how many want each of Passages.
+	Passengers []int
+	// MailContainers each hold mailTons and pay mailPay.
+	MailContainers int
+}
+
+// FreightTons is the total of the freight lots.
+func (t *Traffic) FreightTons() (tons int) {
+	for _, lot := range t.Freight {
+		tons += lot.Tons
+	}
+
+	return
+}
+
+// Revenue is what carrying all of the traffic pays.
+func (t *Traffic) Revenue() (credits int) {
+	credits = t.FreightTons()*t.FreightRate + t.MailContainers*mailPay
+	for id, count := range t.Passengers {
+		credits += count * t.Fare(id)
+	}
+
+	return
+}
+
+// Fare is the price of a ticket in the class of passage.
+func (t *Traffic) Fare(passage int) int {
+	return Passages[passage].Fares[t.Parsecs-1]
+}
+
+// Traffic rolls the freight, passengers and mail waiting at one end of the
+// jump for the other. The worlds' populations, starports, tech levels and
+// zones count, averaged between the two, and nothing waits at an
+// uninhabited world. The dice are the link's own, so it is the same every
+// time it is asked.
+func (g *Galaxy) Traffic(from int, jump *Jump) (traffic *Traffic) {
+	to := jump.Neighbour(from)
+	parsecs := jump.Rating()
+	if parsecs > len(freightRates) {
+		parsecs = len(freightRates)
+	}
+	traffic = &Traffic{From: from, To: to, Parsecs: parsecs, FreightRate: freightRates[parsecs-1],
+		Freight: make([]FreightLot, 0), Passengers: make([]int, len(Passages))}
+	source, destination := g.World(from), g.World(to)
+	if source.PopBase == 0 {
+		return
+	}
+	fromStar, toStar := g.Stars[from], g.Stars[to]
+	// Each parsec after the first puts off some of the trade.
+	distance := 1 - parsecs
+
+	freightDM := (freightDM(source)+freightDM(destination))/2 + distance
+	dice := linkDice(fromStar, toStar, freightSeed)
+	for _, kind := range freightKinds {
+		lots := rollDice(dice, trafficRoll(dice, freightDM+kind.dm))
+		for i := 0; i < lots; i++ {
+			traffic.Freight = append(traffic.Freight, FreightLot{Kind: kind.kind, Tons: d6(dice) * kind.tonsTimes})
+		}
+	}
+
+	passengerDM := (passengerDM(source)+passengerDM(destination))/2 + distance
+	dice = linkDice(fromStar, toStar, passengerSeed)
+	for id, passage := range Passages {
+		traffic.Passengers[id] = rollDice(dice, trafficRoll(dice, passengerDM+passage.dm))
+	}
+
+	dice = linkDice(fromStar, toStar, mailSeed)
+	if twoD6(dice)+mailDM(source, freightDM) >= mailRoll {
+		traffic.MailContainers = d6(dice)
+	}
+
+	return
+}
+
+// trafficRoll rolls 2D6 plus the modifiers on the traffic table, giving the
+// D6 to roll for lots or passengers.
+func trafficRoll(dice *rand.Rand, dm int) int {
+	roll := twoD6(dice) + dm
+	if roll < 1 {
+		return 0
+	}
+	if roll > len(trafficDice) {
+		return trafficDice[len(trafficDice)-1]
+	}
+
+	return trafficDice[roll-1]
+}
+
+// starportDMs modify passenger and freight traffic by starport.
+var starportDMs = map[string]int{"A": 2, "B": 1, "E": -1, "X": -3}
+
+// freightDM is what a world adds to freight traffic to or from it.
+func freightDM(w *World) (dm int) {
+	dm = starportDMs[w.StarPort]
+	switch {
+	case w.PopBase <= 1:
+		dm -= 4
+	case w.PopBase >= 8:
+		dm += 4
+	case w.PopBase >= 6:
+		dm += 2
+	}
+	if w.TechLevelBase <= 6 {
+		dm--
+	} else if w.TechLevelBase >= 9 {
+		dm += 2
+	}
+	switch w.Zone {
+	case AmberZone:
+		dm -= 2
+	case RedZone:
+		dm -= 6
+	}
+
+	return
+}
+
+// passengerDM is what a world adds to passenger traffic to or from it.
+func passengerDM(w *World) (dm int) {
+	dm = starportDMs[w.StarPort]
+	switch {
+	case w.PopBase <= 1:
+		dm -= 4
+	case w.PopBase >= 8:
+		dm += 3
+	case w.PopBase >= 6:
+		dm++
+	}
+	switch w.Zone {
+	case AmberZone:
+		dm++
+	case RedZone:
+		dm -= 4
+	}
+
+	return
+}
+
+// mailDM is the modifier on the mail roll: from -2 to 2 as the freight is
+// light or heavy, less 4 from a world of tech level 5 or less.
+func mailDM(source *World, freightDM int) (dm int) {
+	switch {
+	case freightDM <= -10:
+		dm = -2
+	case freightDM <= -5:
+		dm = -1
+	case freightDM >= 10:
+		dm = 2
+	case freightDM >= 5:
+		dm = 1
+	}
+	if source.TechLevelBase <= 5 {
+		dm -= 4
+	}
+
+	return
+}
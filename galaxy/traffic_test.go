@@ -0,0 +1,50 @@
+package galaxy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTraffic(t *testing.T) {
+	g := New(cube(1), Rules{Classic: true})
+	busy, quiet := 0, 0
+	for from := range g.Stars[:200] {
+		for _, jump := range g.JumpsByStar[from] {
+			traffic := g.Traffic(from, jump)
+			if traffic.To != jump.Neighbour(from) || traffic.Parsecs != jump.Rating() {
+				t.Fatalf("traffic from %d is %+v for a J%d jump", from, traffic, jump.Rating())
+			}
+			if !reflect.DeepEqual(traffic, g.Traffic(from, jump)) {
+				t.Fatalf("traffic from %d to %d changes between calls", from, traffic.To)
+			}
+			if g.World(from).PopBase == 0 {
+				if traffic.Revenue() != 0 {
+					t.Errorf("uninhabited star %d has traffic %+v", from, traffic)
+				}
+				continue
+			}
+			if traffic.Revenue() > 0 {
+				busy++
+			} else {
+				quiet++
+			}
+		}
+	}
+	if busy == 0 || busy < quiet {
+		t.Errorf("%d jumps have traffic and %d none", busy, quiet)
+	}
+}
+
+func TestTrafficModifiers(t *testing.T) {
+	highport := &World{StarPort: "A", PopBase: 9, TechLevelBase: 12, Zone: GreenZone}
+	closed := &World{StarPort: "X", PopBase: 1, TechLevelBase: 3, Zone: RedZone}
+	if freightDM(highport) != 8 || freightDM(closed) != -14 {
+		t.Errorf("freight DMs %d and %d, want 8 and -14", freightDM(highport), freightDM(closed))
+	}
+	if passengerDM(highport) != 5 || passengerDM(closed) != -11 {
+		t.Errorf("passenger DMs %d and %d, want 5 and -11", passengerDM(highport), passengerDM(closed))
+	}
+	if mailDM(highport, 10) != 2 || mailDM(closed, -14) != -6 {
+		t.Errorf("mail DMs %d and %d, want 2 and -6", mailDM(highport, 10), mailDM(closed, -14))
+	}
+}
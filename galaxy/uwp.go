@@ -0,0 +1,129 @@
+package galaxy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TradeCode is a Traveller trade classification, such as "Ag" for an
+// agricultural world.
+type TradeCode string
+
+// The trade classifications a world can have.
+const (
+	Agricultural   TradeCode = "Ag"
+	Asteroid       TradeCode = "As"
+	Desert         TradeCode = "De"
+	HighPopulation TradeCode = "Hi"
+	Industrial     TradeCode = "In"
+	LowPopulation  TradeCode = "Lo"
+	Poor           TradeCode = "Po"
+	Rich           TradeCode = "Ri"
+	Vacuum         TradeCode = "Va"
+	WaterWorld     TradeCode = "Wa"
+)
+
+// eHexDigits are the UWP digits: 0-9, then A-Z skipping I and O.
+const eHexDigits = "0123456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// tradeRule decides whether a world earns a trade code.
+type tradeRule struct {
+	code    TradeCode
+	applies func(w *World) bool
+}
+
+// tradeRules are the classic Traveller trade classifications, in the order
+// they are listed.
+var tradeRules = []tradeRule{
+	{Agricultural, func(w *World) bool {
+		return between(w.AtmosphereBase, 4, 9) && between(w.HydroBase, 4, 8) && between(w.PopBase, 5, 7)
+	}},
+	{Asteroid, func(w *World) bool {
+		return w.SizeBase == 0 && w.AtmosphereBase == 0 && w.HydroBase == 0
+	}},
+	{Desert, func(w *World) bool {
+		return w.AtmosphereBase >= 2 && w.HydroBase == 0
+	}},
+	{HighPopulation, func(w *World) bool {
+		return w.PopBase >= 9
+	}},
+	{Industrial, func(w *World) bool {
+		switch w.AtmosphereBase {
+		case 0, 1, 2, 4, 7, 9:
+			return w.PopBase >= 9
+		}
+		return false
+	}},
+	{LowPopulation, func(w *World) bool {
+		return between(w.PopBase, 1, 3)
+	}},
+	{Poor, func(w *World) bool {
+		return between(w.AtmosphereBase, 2, 5) && between(w.HydroBase, 0, 3)
+	}},
+	{Rich, func(w *World) bool {
+		return (w.AtmosphereBase == 6 || w.AtmosphereBase == 8) && between(w.PopBase, 6, 8) &&
+			between(w.GovernmentBase, 4, 9)
+	}},
+	{Vacuum, func(w *World) bool {
+		return w.AtmosphereBase == 0
+	}},
+	{WaterWorld, func(w *World) bool {
+		return w.HydroBase == 10
+	}},
+}
+
+func between(value, low, high int) bool {
+	return value >= low && value <= high
+}
+
+// eHex writes a value as a UWP digit, clamped to the digits there are.
+func eHex(value int) string {
+	if value < 0 {
+		value = 0
+	} else if value >= len(eHexDigits) {
+		value = len(eHexDigits) - 1
+	}
+
+	return eHexDigits[value : value+1]
+}
+
+// UWP is the world's Universal World Profile, such as A788899-C: starport,
+// size, atmosphere, hydrographics, population, government and law level,
+// then tech level.
+func (w *World) UWP() string {
+	return fmt.Sprintf("%s%s%s%s%s%s%s-%s", w.StarPort, eHex(w.SizeBase), eHex(w.AtmosphereBase),
+		eHex(w.HydroBase), eHex(w.PopBase), eHex(w.GovernmentBase), eHex(w.LawBase), eHex(w.TechLevelBase))
+}
+
+// TradeCodes lists the world's trade classifications in the standard order.
+func (w *World) TradeCodes() (codes []TradeCode) {
+	codes = make([]TradeCode, 0)
+	for _, rule := range tradeRules {
+		if rule.applies(w) {
+			codes = append(codes, rule.code)
+		}
+	}
+
+	return
+}
+
+// HasTradeCode reports whether the world has the trade classification.
+func (w *World) HasTradeCode(code TradeCode) bool {
+	for _, rule := range tradeRules {
+		if rule.code == code {
+			return rule.applies(w)
+		}
+	}
+
+	return false
+}
+
+// TradeCodeList is the world's trade codes separated by spaces, such as "Ag Lo".
+func (w *World) TradeCodeList() string {
+	codes := make([]string, 0)
+	for _, code := range w.TradeCodes() {
+		codes = append(codes, string(code))
+	}
+
+	return strings.Join(codes, " ")
+}
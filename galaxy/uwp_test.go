@@ -0,0 +1,113 @@
+package galaxy
+
+import "testing"
+
+// profile builds a world from UWP codes: size, atmosphere, hydrographics,
+// population, government and law level.
+func profile(size, atm, hydro, pop, gov, law int) *World {
+	return &World{StarPort: "C", SizeBase: size, AtmosphereBase: atm, HydroBase: hydro, PopBase: pop,
+		GovernmentBase: gov, LawBase: law, TechLevelBase: 7}
+}
+
+func TestUWP(t *testing.T) {
+	tests := []struct {
+		world *World
+		want  string
+	}{
+		{&World{StarPort: "A", SizeBase: 7, AtmosphereBase: 8, HydroBase: 8, PopBase: 8, GovernmentBase: 9,
+			LawBase: 9, TechLevelBase: 12}, "A788899-C"},
+		{&World{StarPort: "X", TechLevelBase: 1}, "X000000-1"},
+		{&World{StarPort: "B", SizeBase: 10, AtmosphereBase: 15, HydroBase: 10, PopBase: 10, GovernmentBase: 13,
+			LawBase: 9, TechLevelBase: 18}, "BAFAAD9-J"},
+	}
+	for _, test := range tests {
+		if got := test.world.UWP(); got != test.want {
+			t.Errorf("UWP() = %s, want %s", got, test.want)
+		}
+	}
+}
+
+func TestTradeCodes(t *testing.T) {
+	tests := []struct {
+		name  string
+		world *World
+		code  TradeCode
+		want  bool
+	}{
+		{"Ag", profile(5, 6, 6, 6, 4, 4), Agricultural, true},
+		{"Ag atmosphere too thin", profile(5, 3, 6, 6, 4, 4), Agricultural, false},
+		{"Ag atmosphere too dense", profile(5, 10, 6, 6, 4, 4), Agricultural, false},
+		{"Ag too wet", profile(5, 6, 9, 6, 4, 4), Agricultural, false},
+		{"Ag too dry", profile(5, 6, 3, 6, 4, 4), Agricultural, false},
+		{"Ag too few people", profile(5, 6, 6, 4, 4, 4), Agricultural, false},
+		{"Ag too many people", profile(5, 6, 6, 8, 4, 4), Agricultural, false},
+
+		{"As", profile(0, 0, 0, 3, 2, 2), Asteroid, true},
+		{"As with a size", profile(1, 0, 0, 3, 2, 2), Asteroid, false},
+		{"As with air", profile(0, 1, 0, 3, 2, 2), Asteroid, false},
+
+		{"De", profile(6, 2, 0, 5, 5, 5), Desert, true},
+		{"De with water", profile(6, 2, 1, 5, 5, 5), Desert, false},
+		{"De without air", profile(6, 1, 0, 5, 5, 5), Desert, false},
+
+		{"Hi", profile(6, 6, 6, 9, 5, 5), HighPopulation, true},
+		{"Hi too few people", profile(6, 6, 6, 8, 5, 5), HighPopulation, false},
+
+		{"In vacuum", profile(6, 0, 6, 9, 5, 5), Industrial, true},
+		{"In trace", profile(6, 1, 6, 9, 5, 5), Industrial, true},
+		{"In very thin tainted", profile(6, 2, 6, 9, 5, 5), Industrial, true},
+		{"In thin tainted", profile(6, 4, 6, 10, 5, 5), Industrial, true},
+		{"In standard tainted", profile(6, 7, 6, 9, 5, 5), Industrial, true},
+		{"In dense tainted", profile(6, 9, 6, 9, 5, 5), Industrial, true},
+		{"In standard", profile(6, 6, 6, 9, 5, 5), Industrial, false},
+		{"In too few people", profile(6, 7, 6, 8, 5, 5), Industrial, false},
+
+		{"Lo", profile(6, 6, 6, 1, 5, 5), LowPopulation, true},
+		{"Lo at 3", profile(6, 6, 6, 3, 5, 5), LowPopulation, true},
+		{"Lo uninhabited", profile(6, 6, 6, 0, 0, 0), LowPopulation, false},
+		{"Lo too many people", profile(6, 6, 6, 4, 5, 5), LowPopulation, false},
+
+		{"Po", profile(4, 2, 0, 5, 5, 5), Poor, true},
+		{"Po at the limits", profile(4, 5, 3, 5, 5, 5), Poor, true},
+		{"Po atmosphere too dense", profile(4, 6, 3, 5, 5, 5), Poor, false},
+		{"Po too wet", profile(4, 5, 4, 5, 5, 5), Poor, false},
+		{"Po without air", profile(4, 1, 0, 5, 5, 5), Poor, false},
+
+		{"Ri standard", profile(7, 6, 5, 7, 6, 5), Rich, true},
+		{"Ri dense", profile(7, 8, 5, 6, 4, 5), Rich, true},
+		{"Ri tainted", profile(7, 7, 5, 7, 6, 5), Rich, false},
+		{"Ri too few people", profile(7, 6, 5, 5, 6, 5), Rich, false},
+		{"Ri too many people", profile(7, 6, 5, 9, 6, 5), Rich, false},
+		{"Ri wrong government", profile(7, 6, 5, 7, 3, 5), Rich, false},
+		{"Ri government too high", profile(7, 6, 5, 7, 10, 5), Rich, false},
+
+		{"Va", profile(3, 0, 0, 2, 2, 2), Vacuum, true},
+		{"Va with air", profile(3, 1, 0, 2, 2, 2), Vacuum, false},
+
+		{"Wa", profile(8, 6, 10, 5, 5, 5), WaterWorld, true},
+		{"Wa with land", profile(8, 6, 9, 5, 5, 5), WaterWorld, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.world.HasTradeCode(test.code); got != test.want {
+				t.Errorf("%s HasTradeCode(%s) = %t, want %t", test.world.UWP(), test.code, got, test.want)
+			}
+			listed := false
+			for _, code := range test.world.TradeCodes() {
+				listed = listed || code == test.code
+			}
+			if listed != test.want {
+				t.Errorf("%s TradeCodes() = %v", test.world.UWP(), test.world.TradeCodes())
+			}
+		})
+	}
+}
+
+func TestTradeCodeList(t *testing.T) {
+	if got := profile(0, 0, 0, 2, 2, 2).TradeCodeList(); got != "As Lo Va" {
+		t.Errorf("TradeCodeList() = %q, want %q", got, "As Lo Va")
+	}
+	if got := profile(6, 6, 5, 4, 5, 5).TradeCodeList(); got != "" {
+		t.Errorf("TradeCodeList() = %q, want none", got)
+	}
+}
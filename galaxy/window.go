@@ -0,0 +1,101 @@
+package galaxy
+
+// SectorCache keeps the stars of the sectors a moving window has used
+// recently, so that shifting the window by a sector regenerates only the
+// sectors it gains.
+type SectorCache struct {
+	rules   Rules
+	sectors map[Sector][]*Star
+	// worlds are the mainworlds of each sector's stars, as generated, rolled
+	// the first time the sectors around a galaxy are worked over.
+	worlds map[Sector][]*World
+}
+
+// NewSectorCache returns an empty cache of sectors generated under the rules.
+func NewSectorCache(rules Rules) *SectorCache {
+	return &SectorCache{rules: rules, sectors: make(map[Sector][]*Star), worlds: make(map[Sector][]*World)}
+}
+
+// Stars returns the stars of a sector, generating them if they aren't cached.
+// The stars are shared, so callers must copy them before changing them.
+func (c *SectorCache) Stars(s Sector) []*Star {
+	stars, ok := c.sectors[s]
+	if !ok {
+		stars = SectorStars(s, c.rules)
+		c.sectors[s] = stars
+	}
+
+	return stars
+}
+
+// Worlds returns the mainworlds of a sector's stars, in the same order,
+// rolling them if they aren't cached. Like the stars they are shared.
+func (c *SectorCache) Worlds(s Sector) []*World {
+	worlds, ok := c.worlds[s]
+	if !ok {
+		stars := c.Stars(s)
+		worlds = make([]*World, len(stars))
+		for index, star := range stars {
+			worlds[index] = worldFromStar(star)
+		}
+		c.worlds[s] = worlds
+	}
+
+	return worlds
+}
+
+// Retain drops every cached sector outside the region.
+func (c *SectorCache) Retain(r Region) {
+	for s := range c.sectors {
+		if !r.Contains(s) {
+			delete(c.sectors, s)
+			delete(c.worlds, s)
+		}
+	}
+}
+
+// Len is the number of sectors cached.
+func (c *SectorCache) Len() int {
+	return len(c.sectors)
+}
+
+// Window generates the region like New, but picks each star's jumps with the
+// sectors around the region loaded too. The jumps inside a window are then
+// exactly those of any larger window holding it, so routes across sector
+// borders agree however the window moves. Routes leaving the region are left
+// out. The cache keeps only the sectors the window used.
+func Window(region Region, cache *SectorCache) *Galaxy {
+	context := region.Grow(1)
+	stars := make([]*Star, 0)
+	keep := make([]bool, 0)
+	for _, sector := range context.Sectors() {
+		inside := region.Contains(sector)
+		for _, star := range cache.Stars(sector) {
+			starCopy := *star
+			starCopy.ID = len(stars)
+			stars = append(stars, &starCopy)
+			keep = append(keep, inside)
+		}
+	}
+	cache.Retain(context)
+
+	jumps := findJumps(stars, keep)
+	g := &Galaxy{Stars: make([]*Star, 0)}
+	newIDs := make(map[int]int)
+	for id, star := range stars {
+		if keep[id] {
+			newIDs[id] = len(g.Stars)
+			star.ID = len(g.Stars)
+			g.Stars = append(g.Stars, star)
+		}
+	}
+	for _, jump := range jumps {
+		jump.S1ID = newIDs[jump.S1ID]
+		jump.S2ID = newIDs[jump.S2ID]
+	}
+	g.setJumps(jumps)
+	g.buildWorlds()
+	g.sectors = cache
+
+	return g
+}
@@ -0,0 +1,50 @@
+package galaxy
+
+import (
+	"testing"
+)
+
+type starKey struct {
+	sector Sector
+	index  int
+}
+
+func windowJumps(g *Galaxy, region Region) map[[2]starKey]float32 {
+	result := make(map[[2]starKey]float32)
+	for _, jump := range g.Jumps {
+		s1, s2 := g.Stars[jump.S1ID], g.Stars[jump.S2ID]
+		if region.Contains(s1.Sector) && region.Contains(s2.Sector) {
+			result[[2]starKey{{s1.Sector, s1.Index}, {s2.Sector, s2.Index}}] = jump.Distance
+		}
+	}
+
+	return result
+}
+
+func TestWindowsAgree(t *testing.T) {
+	small := Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 1, Y: 0, Z: 0}}
+	large := small.Grow(1)
+	cache := NewSectorCache(DefaultRules)
+	want := windowJumps(Window(small, cache), small)
+	got := windowJumps(Window(large, cache), small)
+	if len(want) == 0 || len(got) != len(want) {
+		t.Fatalf("larger window has %d jumps inside the smaller one, want %d", len(got), len(want))
+	}
+	for key, distance := range want {
+		if got[key] != distance {
+			t.Errorf("jump %v is %f in the larger window, want %f", key, got[key], distance)
+		}
+	}
+	if cache.Len() != len(large.Grow(1).Sectors()) {
+		t.Errorf("cache holds %d sectors, want %d", cache.Len(), len(large.Grow(1).Sectors()))
+	}
+}
+
+func TestRegionAround(t *testing.T) {
+	region := Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 2, Y: 1, Z: 0}}
+	got := region.Around(Sector{X: -5, Y: 7, Z: 3})
+	want := Region{From: Sector{X: -6, Y: 7, Z: 3}, To: Sector{X: -4, Y: 8, Z: 3}}
+	if got != want {
+		t.Errorf("Around gave %v, want %v", got, want)
+	}
+}
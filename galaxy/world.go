@@ -0,0 +1,435 @@
+package galaxy
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"strconv"
+
+	"github.com/spaolacci/murmur3"
+)
+
+// World is the Traveller style mainworld of a star system. Each value has
+// its dice roll or table index alongside it as a ...Base field.
+type World struct {
+	StarID         int        `json:"star"`
+	StarPort       string     `json:"starPort"`
+	Scout          bool       `json:"scout"`
+	Navy           bool       `json:"navy"`
+	Military       bool       `json:"military"`
+	GasGiants      int        `json:"gasGiants"`
+	Size           int        `json:"size"`
+	SizeBase       int        `json:"sizeBase"`
+	Atmosphere     Atmosphere `json:"atmosphere"`
+	AtmosphereBase int        `json:"atmosphereBase"`
+	Hydro          int        `json:"hydro"`
+	HydroBase      int        `json:"hydroBase"`
+	Population     uint64     `json:"population"`
+	PopBase        int        `json:"popBase"`
+	LawLevel       string     `json:"lawLevel"`
+	LawBase        int        `json:"lawBase"`
+	Government     string     `json:"government"`
+	GovernmentBase int        `json:"governmentBase"`
+	TechLevel      string     `json:"techLevel"`
+	TechLevelBase  int        `json:"techLevelBase"`
+	Zone           Zone       `json:"zone"`
+	// ZoneReason says what put an Amber or Red world in its zone.
+	ZoneReason string `json:"zoneReason,omitempty"`
+}
+
+// Atmosphere describes a world's atmosphere code.
+type Atmosphere struct {
+	Description string `json:"description"`
+	Base        int    `json:"base"`
+	Tainted     bool   `json:"tainted"`
+	Trace       bool   `json:"trace"`
+	VeryThin    bool   `json:"veryThin"`
+	Thin        bool   `json:"thin"`
+	Standard    bool   `json:"standard"`
+	Dense       bool   `json:"dense"`
+	Exotic      bool   `json:"exotic"`
+	Corrosive   bool   `json:"corrosive"`
+	Insidious   bool   `json:"insidious"`
+}
+
+var (
+	noAtmosphere    = Atmosphere{Description: "No atmosphere", Base: 0, Tainted: false, Trace: false, VeryThin: false, Thin: false, Standard: false, Dense: false, Exotic: false, Corrosive: false, Insidious: false}
+	traceAtmosphere = Atmosphere{Description: "Trace", Base: 1, Tainted: false, Trace: true, VeryThin: false, Thin: false, Standard: false, Dense: false, Exotic: false, Corrosive: false, Insidious: false}
+	veryThinTainted = Atmosphere{Description: "Very thin - tainted", Base: 2, Tainted: true, Trace: false, VeryThin: true, Thin: false, Standard: false, Dense: false, Exotic: false, Corrosive: false, Insidious: false}
+	veryThin        = Atmosphere{Description: "Very thin", Base: 3, Tainted: false, Trace: false, VeryThin: true, Thin: false, Standard: false, Dense: false, Exotic: false, Corrosive: false, Insidious: false}
+	thinTainted     = Atmosphere{Description: "Thin - tainted", Base: 4, Tainted: true, Trace: false, VeryThin: false, Thin: true, Standard: false, Dense: false, Exotic: false, Corrosive: false, Insidious: false}
+	thin            = Atmosphere{Description: "Thin", Base: 5, Tainted: false, Trace: false, VeryThin: false, Thin: true, Standard: false, Dense: false, Exotic: false, Corrosive: false, Insidious: false}
+	standard        = Atmosphere{Description: "Standard", Base: 6, Tainted: false, Trace: false, VeryThin: false, Thin: false, Standard: true, Dense: false, Exotic: false, Corrosive: false, Insidious: false}
+	standardTainted = Atmosphere{Description: "Standard - tainted", Base: 7, Tainted: true, Trace: false, VeryThin: false, Thin: false, Standard: true, Dense: false, Exotic: false, Corrosive: false, Insidious: false}
+	dense           = Atmosphere{Description: "Dense", Base: 8, Tainted: false, Trace: false, VeryThin: false, Thin: false, Standard: false, Dense: true, Exotic: false, Corrosive: false, Insidious: false}
+	denseTainted    = Atmosphere{Description: "Dense - tainted", Base: 9, Tainted: true, Trace: false, VeryThin: false, Thin: false, Standard: false, Dense: true, Exotic: false, Corrosive: false, Insidious: false}
+	exotic          = Atmosphere{Description: "Exotic", Base: 10, Tainted: false, Trace: false, VeryThin: false, Thin: false, Standard: false, Dense: false, Exotic: true, Corrosive: false, Insidious: false}
+	corrosive       = Atmosphere{Description: "Corrosive", Base: 11, Tainted: false, Trace: false, VeryThin: false, Thin: false, Standard: false, Dense: false, Exotic: false, Corrosive: true, Insidious: false}
+	insidious       = Atmosphere{Description: "Insidious", Base: 12, Tainted: false, Trace: false, VeryThin: false, Thin: false, Standard: false, Dense: false, Exotic: false, Corrosive: false, Insidious: true}
+
+	atmospheres = []Atmosphere{
+		noAtmosphere, traceAtmosphere, veryThinTainted, veryThin, thinTainted, thin, standard, standardTainted, dense, denseTainted, exotic, corrosive, insidious, insidious, insidious, insidious, insidious,
+	}
+)
+
+// worldHash seeds a star's world dice from its sector's coordinates and its
+// index in the sector, each written as four little-endian bytes (negative
+// coordinates as their two's complement) and hashed with 64-bit murmur3. The
+// seed depends on nothing else, so a star's world is the same on every run and
+// platform and in every region that contains it, and no two stars share one.
+func worldHash(fromStar *Star) *rand.Rand {
+	id := murmur3.New64()
+	buf := make([]byte, 16)
+	binary.LittleEndian.PutUint32(buf[0:], uint32(fromStar.Sector.X))
+	binary.LittleEndian.PutUint32(buf[4:], uint32(fromStar.Sector.Y))
+	binary.LittleEndian.PutUint32(buf[8:], uint32(fromStar.Sector.Z))
+	binary.LittleEndian.PutUint32(buf[12:], uint32(fromStar.Index))
+	_, err := id.Write(buf)
+	if err != nil {
+		print("Failed to hash world seed")
+	}
+
+	return rand.New(rand.NewSource(int64(id.Sum64())))
+}
+
+func worldFromStar(fromStar *Star) (newWorld *World) {
+	random1s := worldHash(fromStar)
+
+	starPort := getStarPort(random1s)
+	size, sizeBase := getSize(random1s, fromStar.HasCloseCompanion())
+	atmosphereDescription, atmosphereBase := getAtmosphere(random1s, sizeBase)
+	hydro, hydroBase := getHydro(random1s, sizeBase)
+	population, popBase := getPopulation(random1s)
+	lawLevel, lawBase := getLawLevel(random1s, popBase)
+	government, governmentBase := getGovernment(random1s, popBase)
+	techLevel, tl := getTechLevel(random1s, starPort, size, atmosphereBase, hydroBase, popBase, governmentBase)
+
+	newWorld = &World{
+		StarID:         fromStar.ID,
+		StarPort:       starPort,
+		Scout:          getScout(random1s, starPort),
+		Navy:           getNavy(random1s, starPort),
+		Military:       getMilitary(random1s, starPort, popBase, atmospheres[atmosphereBase].Tainted),
+		GasGiants:      getGasGiants(random1s),
+		Size:           size,
+		SizeBase:       sizeBase,
+		Atmosphere:     atmosphereDescription,
+		AtmosphereBase: atmosphereBase,
+		Hydro:          hydro,
+		HydroBase:      hydroBase,
+		Population:     population,
+		PopBase:        popBase,
+		LawLevel:       lawLevel,
+		LawBase:        lawBase,
+		Government:     government,
+		GovernmentBase: governmentBase,
+		TechLevel:      techLevel,
+		TechLevelBase:  tl,
+	}
+	fitGasGiants(fromStar, newWorld)
+	newWorld.Zone, newWorld.ZoneReason = travelZone(newWorld, starDice(fromStar, zoneSeed))
+
+	return
+}
+
+func getStarPort(rand *rand.Rand) (portType string) {
+	huh := twoD6(rand)
+	switch huh {
+	case 2, 3, 4:
+		portType = "A"
+	case 5, 6:
+		portType = "B"
+	case 7, 8:
+		portType = "C"
+	case 9:
+		portType = "D"
+	case 10, 11:
+		portType = "E"
+	case 12:
+		portType = "X"
+	default:
+		portType = "S"
+	}
+
+	return
+}
+
+// getSize rolls the world's size, 2 smaller if a close companion star keeps
+// it from gathering material.
+func getSize(rand *rand.Rand, closeCompanion bool) (kilometers int, base int) {
+	base = twoD6(rand) - 2
+	if closeCompanion {
+		base -= 2
+		if base < 0 {
+			base = 0
+		}
+	}
+	kilometers = 1600 * base
+
+	return
+}
+
+func getAtmosphereFromID(fromAtmosphereBase int) (result Atmosphere) {
+	switch fromAtmosphereBase {
+	default:
+		return noAtmosphere
+	case 0:
+		return noAtmosphere
+	case 1:
+		return traceAtmosphere
+	case 2:
+		return veryThinTainted
+	case 3:
+		return veryThin
+	case 4:
+		return thinTainted
+	case 5:
+		return thin
+	case 6:
+		return standard
+	case 7:
+		return standardTainted
+	case 8:
+		return dense
+	case 9:
+		return denseTainted
+	case 10:
+		return exotic
+	case 11:
+		return corrosive
+	case 12, 13, 14, 15, 16:
+		return insidious
+	}
+}
+
+func getAtmosphere(rand *rand.Rand, size int) (result Atmosphere, base int) {
+	base = twoD6(rand) + size - 7
+	if base < 0 {
+		base = 0
+	}
+	result = getAtmosphereFromID(base)
+	return
+}
+
+func getHydro(rand *rand.Rand, size int) (percent int, base int) {
+	percent = 10 * (twoD6(rand) + size - 7)
+	if percent < 0 {
+		percent = 0.0
+	} else if percent > 100 {
+		percent = 100
+	}
+	base = percent / 10
+
+	return
+}
+
+func getPopulation(rand *rand.Rand) (total uint64, base int) {
+	base = twoD6(rand) - 2.0
+	log := rand.Float32()
+	if base < 0 {
+		base = 0
+	} else if base > 10 {
+		base = 10
+	}
+	if base < 1 {
+		total = 0
+
+		return
+	}
+	total = uint64(math.Exp(float64(float32(base)+log) * math.Log(10)))
+
+	return
+}
+
+var govByBase = []string{
+	"No government",
+	"Company/Corporation",
+	"Participating Democracy",
+	"Self-Perpetuating Oligarchy",
+	"Representative Democracy",
+	"Feudal Technocracy",
+	"Captive Government",
+	"Balkanization",
+	"Civil Service Bureaucracy",
+	"Impersonal Bureaucracy",
+	"Charismatic Dictator",
+	"Non-Charismatic Leader",
+	"Charismatic Oligarchy",
+	"Religious Dictatorship",
+}
+
+func getGovernment(rand *rand.Rand, popBase int) (description string, base int) {
+	base = d6(rand) + d6(rand) + popBase - 7
+	if base < 0 {
+		base = 0
+	} else if base >= len(govByBase) {
+		base = len(govByBase) - 1
+	}
+	description = govByBase[base]
+
+	return
+}
+
+var lawLevelByBase = []string{
+	"No Prohibitions",
+	"Body pistols explosives & poison gas prohibited",
+	"Portable energy weapons prohibited",
+	"Military weapons (automatics) prohibited",
+	"Light assault weapons prohibited",
+	"Personal firearms prohibited",
+	"Most firearms (except shotgun) prohibited all weapons discouraged",
+	"Shotguns prohibited",
+	"Long bladed weapons prohibited",
+	"Possession of any weapon outside residence prohibited",
+}
+
+func getLawLevel(rand *rand.Rand, govBase int) (description string, base int) {
+	base = d6(rand) + d6(rand) + govBase - 7
+	if base < 0 {
+		base = 0
+	} else if base >= len(lawLevelByBase) {
+		base = len(lawLevelByBase) - 1
+	}
+	description = lawLevelByBase[base]
+
+	return
+}
+
+func getTechLevel(rand *rand.Rand, starPort string, size int, atm int, hydro int, pop int, gov int) (techLevel string, tl int) {
+	diceModifier := 0
+	switch starPort {
+	case "A":
+		diceModifier = 6
+	case "B":
+		diceModifier = 4
+	case "C":
+		diceModifier = 2
+	case "X":
+		diceModifier = -4
+	}
+	switch size {
+	case 0, 1:
+		diceModifier += 2
+	case 2, 3, 4:
+		diceModifier++
+	}
+	if atm < 4 || atm > 9 {
+		diceModifier++
+	}
+	if hydro > 8 {
+		diceModifier += hydro - 8
+	}
+	if pop > 0 && pop < 6 {
+		diceModifier++
+	}
+	if pop > 8 {
+		diceModifier += 2 * (pop - 8)
+	}
+	if gov == 0 || gov == 5 {
+		diceModifier++
+	} else if gov == 13 {
+		diceModifier -= 2
+	}
+	tl = d6(rand) + diceModifier
+	if tl < 1 {
+		tl = 1
+	}
+	techLevel = techLevelCode(tl)
+
+	return
+}
+
+// techLevelCode writes a tech level as its UWP digit.
+func techLevelCode(tl int) (techLevel string) {
+	if tl > 9 {
+		switch tl {
+		case 10:
+			techLevel = "A"
+		case 11:
+			techLevel = "B"
+		case 12:
+			techLevel = "C"
+		case 13:
+			techLevel = "D"
+		case 14:
+			techLevel = "E"
+		case 15:
+			techLevel = "F"
+		case 16:
+			techLevel = "G"
+		case 17:
+			techLevel = "H"
+		case 18:
+			techLevel = "J"
+		case 19:
+			techLevel = "K"
+		case 20:
+			techLevel = "L"
+		case 21:
+			techLevel = "M"
+		case 22:
+			techLevel = "N"
+		case 23:
+			techLevel = "O"
+		case 24:
+			techLevel = "P"
+		case 25:
+			techLevel = "Q"
+		case 26:
+			techLevel = "R"
+		case 27:
+			techLevel = "S"
+		case 28:
+			techLevel = "T"
+		case 29:
+			techLevel = "U"
+		case 30:
+			techLevel = "V"
+		case 31:
+			techLevel = "W"
+		case 32:
+			techLevel = "X"
+		case 33:
+			techLevel = "Y"
+		case 34:
+			techLevel = "Z"
+		default:
+			techLevel = "9"
+		}
+	} else {
+		techLevel = strconv.Itoa(tl)
+	}
+
+	return
+}
+
+func twoD6(rand *rand.Rand) (result int) {
+	result = d6(rand) + d6(rand)
+
+	return
+}
+
+func d6(rand *rand.Rand) (result int) {
+	result = rand.Intn(6) + 1
+
+	return
+}
+
+func getGasGiants(rand *rand.Rand) (gasGiants int) {
+	if twoD6(rand) < 10 {
+		switch twoD6(rand) {
+		case 2, 3, 4, 5, 6, 7, 8, 9:
+			gasGiants = 1
+		case 10, 11:
+			gasGiants = 2
+		case 12, 13:
+			gasGiants = 3
+		default:
+			gasGiants = 1
+		}
+	} else {
+		gasGiants = 0
+	}
+
+	return
+}
@@ -0,0 +1,102 @@
+package galaxy
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenSectors cover negative and large coordinates as well as the origin.
+var goldenSectors = []Sector{{X: 0, Y: 0, Z: 0}, {X: -1, Y: 2, Z: -3}, {X: 40000, Y: -7, Z: 12}}
+
+// goldenStars is how many stars of each golden sector are checked.
+const goldenStars = 40
+
+// goldenFiles pins the classic rules, which must never change, as well as
+// the current ones.
+var goldenFiles = map[string]Rules{
+	"worlds.golden":          {Classic: true},
+	"worlds_extended.golden": DefaultRules,
+}
+
+// goldenWorlds lists the stars, worlds, companions and names of the first
+// stars of each golden sector, one per line, keyed by sector and index rather
+// than star ID. Under the extended rules the last stars of each sector, the
+// remnants and brown dwarfs, are listed too.
+func goldenWorlds(rules Rules) []byte {
+	var out bytes.Buffer
+	for _, sector := range goldenSectors {
+		stars := SectorStars(sector, rules)
+		checked := append([]*Star{}, stars[:goldenStars]...)
+		if !rules.Classic {
+			checked = append(checked, stars[len(stars)-goldenStars:]...)
+		}
+		for _, star := range checked {
+			world := worldFromStar(star)
+			fmt.Fprintf(&out, "%d,%d,%d/%d %s %s size %d atm %d hydro %d pop %d gov %d law %d tech %d gg %d scout %t navy %t military %t zone %s companions %q name %q\n",
+				sector.X, sector.Y, sector.Z, star.Index, star.Spectral(), world.StarPort, world.SizeBase, world.AtmosphereBase,
+				world.HydroBase, world.PopBase, world.GovernmentBase, world.LawBase, world.TechLevelBase,
+				world.GasGiants, world.Scout, world.Navy, world.Military, world.Zone, star.CompanionList(), star.Name())
+		}
+	}
+
+	return out.Bytes()
+}
+
+// TestWorldsGolden fails if star or world generation changes. Run with
+// -update after a deliberate change to rewrite the golden files in testdata.
+func TestWorldsGolden(t *testing.T) {
+	for name, rules := range goldenFiles {
+		t.Run(name, func(t *testing.T) {
+			checkGolden(t, filepath.Join("testdata", name), goldenWorlds(rules))
+		})
+	}
+}
+
+func checkGolden(t *testing.T, golden string, got []byte) {
+	if *update {
+		if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotLines := bytes.Split(got, []byte("\n"))
+	for i, line := range bytes.Split(want, []byte("\n")) {
+		if i >= len(gotLines) || !bytes.Equal(line, gotLines[i]) {
+			t.Fatalf("world %d differs from %s:\nwant %s", i, golden, line)
+		}
+	}
+	if len(got) != len(want) {
+		t.Fatalf("generated %d bytes of worlds, %s has %d", len(got), golden, len(want))
+	}
+}
+
+// TestWorldSeedIgnoresRegion rolls a sector inside a larger region and on its
+// own, where its stars have other IDs, and expects the same worlds.
+func TestWorldSeedIgnoresRegion(t *testing.T) {
+	sector := Sector{X: 1, Y: 0, Z: 1}
+	small := New(Region{From: sector, To: sector}, DefaultRules)
+	large := New(cube(2), DefaultRules)
+	for id, star := range small.Stars {
+		other, ok := large.Find(star.Sector, star.Index)
+		if !ok {
+			t.Fatalf("star %d missing from the larger region", id)
+		}
+		if other == id {
+			t.Fatalf("star %d has the same ID in both regions", id)
+		}
+		world := *large.World(other)
+		world.StarID = id
+		if *small.World(id) != world {
+			t.Fatalf("star %d: world depends on the region", id)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package galaxy
+
+const (
+	// xboatImportance is the Importance that puts a world on the X-boat
+	// network.
+	xboatImportance = 4
+	// xboatJump is the X-boats' jump drive rating.
+	xboatJump = 4
+	// xboatReach is the most jumps one link of the network may take. Worlds
+	// further apart are only joined through others.
+	xboatReach = 8
+)
+
+// Importance is the world's T5 importance extension: a point each for an A
+// or B starport, tech level A or more, tech level G or more, each of the Ag,
+// Hi, In and Ri trade codes and having both naval and scout bases, less a
+// point each for a D, E or X starport, tech level 8 or less and population 6
+// or less.
+func (w *World) Importance() (importance int) {
+	switch w.StarPort {
+	case "A", "B":
+		importance++
+	case "D", "E", "X":
+		importance--
+	}
+	if w.TechLevelBase >= 10 {
+		importance++
+	}
+	if w.TechLevelBase >= 16 {
+		importance++
+	}
+	if w.TechLevelBase <= 8 {
+		importance--
+	}
+	if w.PopBase <= 6 {
+		importance--
+	}
+	for _, code := range []TradeCode{Agricultural, HighPopulation, Industrial, Rich} {
+		if w.HasTradeCode(code) {
+			importance++
+		}
+	}
+	if w.Navy && w.Scout {
+		importance++
+	}
+
+	return
+}
+
+// xboatNetwork is the X-boat network: the jumps the X-boats fly and the stars
+// they call at.
+type xboatNetwork struct {
+	jumps []*Jump
+	stars map[int]bool
+}
+
+// xboatLink is the shortest way between two important worlds.
+type xboatLink struct {
+	from, to int
+	jumps    []*Jump
+	distance float32
+}
+
+// XBoatRoutes returns the jumps the X-boat network flies. It is built the
+// first time it is asked for, and again after the worlds or jumps change.
+func (g *Galaxy) XBoatRoutes() []*Jump {
+	return g.xboatNetwork().jumps
+}
+
+// OnXBoatNetwork reports whether the X-boats call at the star, which they
+// may do on their way to worlds beyond the galaxy.
+func (g *Galaxy) OnXBoatNetwork(starID int) bool {
+	return g.xboatNetwork().stars[starID]
+}
+
+// XBoatStars returns the stars the X-boats call at.
+func (g *Galaxy) XBoatStars() (results []*Star) {
+	results = make([]*Star, 0)
+	for _, star := range g.Stars {
+		if g.OnXBoatNetwork(star.ID) {
+			results = append(results, star)
+		}
+	}
+
+	return
+}
+
+func (g *Galaxy) xboatNetwork() *xboatNetwork {
+	if g.xboats == nil {
+		g.xboats = g.buildXBoats()
+	}
+
+	return g.xboats
+}
+
+// buildXBoats links the important worlds, those with xboatImportance, by the
+// fewest jumps an X-boat can make between them, never calling at a Red zone.
+// Two worlds are linked unless a third is linked to both by shorter links, so
+// the X-boats go through it instead. Links are compared by jumps, then
+// parsecs, then their stars' keys, and are worked out over the galaxy's
+// surroundings, so each depends only on the worlds near it and a window's
+// part of the network is the same in any larger window.
+func (g *Galaxy) buildXBoats() (network *xboatNetwork) {
+	network = &xboatNetwork{jumps: make([]*Jump, 0), stars: make(map[int]bool)}
+	around := g.surroundings()
+	important := make(map[int]bool)
+	for id, world := range around.worlds {
+		if world.Importance() >= xboatImportance {
+			important[id] = true
+		}
+	}
+	neighbours := around.neighbours(func(jump *Jump) bool {
+		return jump.Rating() <= xboatJump && around.worlds[jump.S1ID].Zone != RedZone &&
+			around.worlds[jump.S2ID].Zone != RedZone
+	})
+
+	links := make(map[[2]int]*xboatLink)
+	linked := make(map[int][]*xboatLink)
+	for id := range around.stars {
+		if !important[id] {
+			continue
+		}
+		for _, link := range xboatLinks(id, important, neighbours, around.keys) {
+			link := link
+			links[[2]int{link.from, link.to}] = &link
+			links[[2]int{link.to, link.from}] = &link
+			linked[link.from] = append(linked[link.from], &link)
+			linked[link.to] = append(linked[link.to], &link)
+		}
+	}
+	shorter := func(a, b *xboatLink) bool {
+		if len(a.jumps) != len(b.jumps) {
+			return len(a.jumps) < len(b.jumps)
+		}
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		if a.from != b.from {
+			return around.keys[a.from] < around.keys[b.from]
+		}
+
+		return around.keys[a.to] < around.keys[b.to]
+	}
+	between := func(link *xboatLink) bool {
+		for _, first := range linked[link.from] {
+			via := first.from
+			if via == link.from {
+				via = first.to
+			}
+			if second := links[[2]int{via, link.to}]; second != nil && shorter(first, link) && shorter(second, link) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	flown := make(map[*Jump]bool)
+	for id := range around.stars {
+		for _, link := range linked[id] {
+			if link.from != id || between(link) {
+				continue
+			}
+			for _, jump := range link.jumps {
+				if !around.inside(jump.S1ID) && !around.inside(jump.S2ID) || flown[jump] {
+					continue
+				}
+				flown[jump] = true
+				for _, id := range []int{jump.S1ID, jump.S2ID} {
+					if around.inside(id) {
+						network.stars[id] = true
+					}
+				}
+				if around.inside(jump.S1ID) && around.inside(jump.S2ID) {
+					network.jumps = append(network.jumps, jump)
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// xboatLinks searches out from an important world, xboatReach jumps at most,
+// for the fewest jumps to each important world with a later key.
+func xboatLinks(from int, important map[int]bool, neighbours map[int][]*Jump, keys []string) (links []xboatLink) {
+	reachedBy := map[int]*Jump{from: nil}
+	frontier := []int{from}
+	for hops := 0; hops < xboatReach && len(frontier) > 0; hops++ {
+		next := make([]int, 0)
+		for _, at := range frontier {
+			for _, jump := range neighbours[at] {
+				star := jump.Neighbour(at)
+				if _, seen := reachedBy[star]; seen {
+					continue
+				}
+				reachedBy[star] = jump
+				next = append(next, star)
+				if important[star] && keys[star] > keys[from] {
+					jumps, distance := tracePath(from, star, reachedBy)
+					links = append(links, xboatLink{from: from, to: star, jumps: jumps, distance: distance})
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return
+}
+
+// tracePath follows the jumps a search reached a star by back to the start.
+func tracePath(from, to int, reachedBy map[int]*Jump) (jumps []*Jump, distance float32) {
+	for at := to; at != from; {
+		jump := reachedBy[at]
+		jumps = append([]*Jump{jump}, jumps...)
+		distance += jump.Distance
+		at = jump.Neighbour(at)
+	}
+
+	return
+}
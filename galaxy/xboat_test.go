@@ -0,0 +1,109 @@
+package galaxy
+
+import (
+	"testing"
+)
+
+func TestImportance(t *testing.T) {
+	tests := []struct {
+		uwp        string
+		navy       bool
+		scout      bool
+		importance int
+	}{
+		// Port A, TL 12, Hi and In, with both bases: 1 + 1 + 2 + 1.
+		{"A877A00-C", true, true, 5},
+		// Port X, TL 2, population 3: -1 - 1 - 1.
+		{"X420300-2", false, false, -3},
+		// Port C, TL 9, population 7, Ag and Ri: 1 each.
+		{"C566744-9", false, false, 2},
+	}
+	for _, test := range tests {
+		edit, err := ParseUWP(test.uwp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		edit.Navy, edit.Scout = &test.navy, &test.scout
+		world := edit.apply(&World{})
+		if got := world.Importance(); got != test.importance {
+			t.Errorf("%s has importance %d, want %d", test.uwp, got, test.importance)
+		}
+	}
+}
+
+func TestXBoatNetwork(t *testing.T) {
+	g := New(cube(2), Rules{Classic: true})
+	routes := g.XBoatRoutes()
+	if len(routes) == 0 {
+		t.Fatal("no X-boat routes")
+	}
+	stars := make(map[int]bool)
+	for _, jump := range routes {
+		if jump.Rating() > xboatJump {
+			t.Errorf("X-boats fly a J%d jump", jump.Rating())
+		}
+		for _, id := range []int{jump.S1ID, jump.S2ID} {
+			stars[id] = true
+			if g.World(id).Zone == RedZone {
+				t.Errorf("X-boats call at Red zone star %d", id)
+			}
+		}
+	}
+	for id := range stars {
+		if !g.OnXBoatNetwork(id) {
+			t.Errorf("X-boats fly to star %d but don't call there", id)
+		}
+	}
+	for _, star := range g.StarsWithBase(WayStation) {
+		if !g.World(star.ID).Scout || !g.OnXBoatNetwork(star.ID) {
+			t.Errorf("way station at star %d off the network or without a scout base", star.ID)
+		}
+	}
+
+	// Closing a world on the network takes it off.
+	closed := g.XBoatStars()[0]
+	red := RedZone
+	c := NewCampaign()
+	c.Star(closed.Key()).World = &WorldEdit{Zone: &red}
+	g.SetCampaign(c)
+	if g.OnXBoatNetwork(closed.ID) {
+		t.Errorf("X-boats still call at star %d after it was made a Red zone", closed.ID)
+	}
+}
+
+func TestXBoatNetworkIsStable(t *testing.T) {
+	cache := NewSectorCache(Rules{Classic: true})
+	large := Window(cube(2), cache)
+	small := Window(Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}}, cache)
+	routes := func(g *Galaxy) map[string]bool {
+		keys := make(map[string]bool)
+		for _, jump := range g.XBoatRoutes() {
+			s1, s2 := g.Stars[jump.S1ID], g.Stars[jump.S2ID]
+			if s1.Sector == small.Region().From && s2.Sector == small.Region().From {
+				keys[s1.Key()+" "+s2.Key()] = true
+			}
+		}
+
+		return keys
+	}
+	want, got := routes(large), routes(small)
+	if len(want) == 0 {
+		t.Fatal("no X-boat routes compared")
+	}
+	for key := range want {
+		if !got[key] {
+			t.Errorf("X-boat route %s is missing from the smaller region", key)
+		}
+	}
+	for key := range got {
+		if !want[key] {
+			t.Errorf("X-boat route %s is missing from the larger region", key)
+		}
+	}
+	for _, star := range small.Stars {
+		other, _ := large.Find(star.Sector, star.Index)
+		if small.OnXBoatNetwork(star.ID) != large.OnXBoatNetwork(other) {
+			t.Errorf("X-boats call at star %s in one region only", star.Key())
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package galaxy
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// Zone is a world's travel zone, the warning the Travellers' Aid Society
+// gives visitors.
+type Zone string
+
+// The travel zones. Amber worlds call for caution; Red ones are interdicted.
+const (
+	GreenZone Zone = "Green"
+	AmberZone Zone = "Amber"
+	RedZone   Zone = "Red"
+)
+
+// Zones lists the travel zones from safest to most dangerous.
+var Zones = []Zone{GreenZone, AmberZone, RedZone}
+
+// zoneSeed seeds the dice for the events that close worlds, "zone" in ASCII.
+const zoneSeed = 0x7a6f6e65
+
+// Points of danger a world scores; amberPoints make it Amber and redPoints
+// Red.
+const (
+	amberPoints = 3
+	redPoints   = 5
+)
+
+// zoneHazard scores something about a world that makes it dangerous.
+type zoneHazard struct {
+	reason  string
+	points  int
+	applies func(w *World) bool
+}
+
+var zoneHazards = []zoneHazard{
+	{"extreme law", 2, func(w *World) bool { return w.LawBase >= len(lawLevelByBase)-1 }},
+	{"lawless", 1, func(w *World) bool { return w.LawBase == 0 && w.PopBase > 0 }},
+	{"balkanized", 2, func(w *World) bool { return w.GovernmentBase == 7 }},
+	{"religious dictatorship", 2, func(w *World) bool { return w.GovernmentBase == 13 }},
+	{"captive government", 1, func(w *World) bool { return w.GovernmentBase == 6 }},
+	{"charismatic dictator", 1, func(w *World) bool { return w.GovernmentBase == 10 }},
+	{"corrosive atmosphere", 2, func(w *World) bool { return w.AtmosphereBase == 11 }},
+	{"insidious atmosphere", 3, func(w *World) bool { return w.AtmosphereBase >= 12 }},
+}
+
+// zoneEvents are the upheavals a world may be going through, by the 2D6
+// roll that brings them. They close the world as the points say.
+var zoneEvents = map[int]zoneHazard{
+	11: {reason: "civil unrest", points: 2},
+	12: {reason: "plague", points: 5},
+}
+
+// travelZone scores the world's law, government and atmosphere, and rolls
+// for an event, to give it a zone and the reasons for it.
+func travelZone(w *World, dice *rand.Rand) (zone Zone, reason string) {
+	points := 0
+	reasons := make([]string, 0)
+	for _, hazard := range zoneHazards {
+		if hazard.applies(w) {
+			points += hazard.points
+			reasons = append(reasons, hazard.reason)
+		}
+	}
+	if event, ok := zoneEvents[twoD6(dice)]; ok {
+		points += event.points
+		reasons = append(reasons, event.reason)
+	}
+
+	zone = GreenZone
+	if points >= redPoints {
+		zone = RedZone
+	} else if points >= amberPoints {
+		zone = AmberZone
+	}
+	if zone != GreenZone {
+		reason = strings.Join(reasons, ", ")
+	}
+
+	return
+}
+
+// Code is the zone's letter in the Second Survey formats: A, R, or empty for
+// Green.
+func (z Zone) Code() string {
+	switch z {
+	case AmberZone:
+		return "A"
+	case RedZone:
+		return "R"
+	default:
+		return ""
+	}
+}
+
+// validZone reports whether the zone is one of Zones.
+func validZone(zone Zone) bool {
+	for _, known := range Zones {
+		if zone == known {
+			return true
+		}
+	}
+
+	return false
+}
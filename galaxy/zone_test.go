@@ -0,0 +1,54 @@
+package galaxy
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestTravelZones(t *testing.T) {
+	// A die that never rolls an event: 2D6 of 2 (each Intn(6) returns 0).
+	calm := rand.New(constantSource(0))
+	tests := []struct {
+		world World
+		zone  Zone
+	}{
+		{World{PopBase: 6, LawBase: 4, GovernmentBase: 4, AtmosphereBase: 6}, GreenZone},
+		{World{PopBase: 6, LawBase: 9, GovernmentBase: 10, AtmosphereBase: 6}, AmberZone},
+		{World{PopBase: 6, LawBase: 9, GovernmentBase: 7, AtmosphereBase: 12}, RedZone},
+	}
+	for _, test := range tests {
+		zone, reason := travelZone(&test.world, calm)
+		if zone != test.zone || (zone == GreenZone) != (reason == "") {
+			t.Errorf("%s world is %s zone, %q", test.world.UWP(), zone, reason)
+		}
+	}
+
+	g := New(cube(2), DefaultRules)
+	green, amber, red := len(g.StarsInZone(GreenZone)), len(g.StarsInZone(AmberZone)), len(g.StarsInZone(RedZone))
+	if green+amber+red != len(g.Stars) || red == 0 || red > amber || amber > green/5 {
+		t.Fatalf("%d green, %d amber and %d red worlds", green, amber, red)
+	}
+}
+
+func TestEditRezones(t *testing.T) {
+	g := New(Region{From: Sector{X: 0, Y: 0, Z: 0}, To: Sector{X: 0, Y: 0, Z: 0}}, DefaultRules)
+	balkanized, extreme := 7, len(lawLevelByBase)-1
+	green := GreenZone
+	c := NewCampaign()
+	c.Star(g.Stars[0].Key()).World = &WorldEdit{Government: &balkanized, Law: &extreme}
+	c.Star(g.Stars[1].Key()).World = &WorldEdit{Government: &balkanized, Law: &extreme, Zone: &green}
+	g.SetCampaign(c)
+	if world := g.World(0); world.Zone == GreenZone || !strings.Contains(world.ZoneReason, "balkanized") {
+		t.Errorf("balkanized world with extreme law is %s zone, %q", world.Zone, world.ZoneReason)
+	}
+	if world := g.World(1); world.Zone != GreenZone || world.ZoneReason != "" {
+		t.Errorf("world edited to Green is %s zone, %q", world.Zone, world.ZoneReason)
+	}
+}
+
+// constantSource is a rand.Source that always returns the same number.
+type constantSource int64
+
+func (s constantSource) Int63() int64 { return int64(s) }
+func (s constantSource) Seed(int64)   {}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"virtualsoundnw.com/play/gogi3/galaxy"
+)
+
+// generateUsage heads the generate subcommand's help.
+const generateUsage = `usage: galaxy3d generate [flags]
+
+Writes a report on a block of sectors without opening a window. A build tagged
+headless, CGO_ENABLED=0 go build -tags headless, has no window and runs only
+this subcommand, so it needs neither cgo nor X11.
+
+Flags:
+`
+
+// runGenerate is the headless "generate" subcommand: it builds the stars and
+// jumps for a block of sectors and writes a report without opening a window.
+func runGenerate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), generateUsage)
+		flags.PrintDefaults()
+	}
+	settings := addConfigFlags(flags)
+	output := flags.String("o", "", "report file to write, or - for standard output (default traveler-report.<format>)")
+	format := flags.String("format", "csv", "report format: csv, text, sec or json")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %v", flags.Args())
+	}
+	report, ok := reportFormats[*format]
+	if !ok && *format != "sec" {
+		return fmt.Errorf("unknown report format %q", *format)
+	}
+	if *output == "" {
+		*output = "traveler-report." + *format
+	}
+	cfg, err := settings.config()
+	if err != nil {
+		return err
+	}
+
+	var g *galaxy.Galaxy
+	if cfg.Load != "" {
+		g, err = loadGalaxy(cfg.Load)
+		if err != nil {
+			return err
+		}
+	} else {
+		g = galaxy.New(cfg.Region, cfg.Rules)
+	}
+	c := g.Campaign()
+	if cfg.Campaign != "" {
+		c, err = loadCampaign(cfg.Campaign)
+		if err != nil {
+			return err
+		}
+	}
+	if cfg.Names != "" {
+		names, err := loadNames(cfg.Names)
+		if err != nil {
+			return err
+		}
+		c.ImportNames(names)
+	}
+	g.SetCampaign(c)
+	if len(g.Stars) == 0 {
+		return fmt.Errorf("no stars generated")
+	}
+	starID, _ := g.ConnectedStar()
+	worlds := g.ConnectedWorlds(starID)
+
+	if *format == "sec" {
+		return generateSEC(g, worlds, cfg.Projection, *output)
+	}
+	if *output == "-" {
+		return report(g, os.Stdout, worlds)
+	}
+
+	return writeFile(*output, func(w io.Writer) error {
+		return report(g, w, worlds)
+	})
+}
+
+// generateSEC writes SEC files, or to standard output if the worlds fit in
+// one map sector, and warns of the stars left out for sharing a hex.
+func generateSEC(g *galaxy.Galaxy, worlds []*galaxy.World, p galaxy.Projection, output string) (err error) {
+	var dropped []*galaxy.World
+	if output != "-" {
+		_, dropped, err = writeSECFiles(g, worlds, p, output)
+	} else if sectors := len(p.Split(g, worlds)); sectors > 1 {
+		return fmt.Errorf("the region spans %d map sectors; write them to files with -o", sectors)
+	} else {
+		dropped, err = g.WriteSEC(os.Stdout, worlds, p)
+	}
+	for _, name := range droppedStars(g, dropped, p) {
+		fmt.Fprintf(os.Stderr, "galaxy3d generate: left %s out of the SEC export; a more populous world has its hex\n", name)
+	}
+
+	return
+}
@@ -0,0 +1,16 @@
+//go:build headless
+// +build headless
+
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// runWindow stands in for the window in a headless build, which can only
+// generate.
+func runWindow(args []string) {
+	fmt.Fprintln(os.Stderr, "galaxy3d: built headless; only the generate subcommand is available")
+	os.Exit(2)
+}
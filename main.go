@@ -0,0 +1,24 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+// main runs the generate subcommand, or opens the window. A build tagged
+// headless has no window, so it needs neither cgo nor X11.
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		err := runGenerate(os.Args[2:])
+		if err == flag.ErrHelp {
+			return
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "galaxy3d generate:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	runWindow(os.Args[1:])
+}
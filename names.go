@@ -0,0 +1,48 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"fmt"
+	"html"
+
+	"github.com/goki/gi/gi3d"
+)
+
+// labelHeight is how far above its star a name label floats.
+const labelHeight = 0.004
+
+// htmlName is the star's name escaped for the panel and labels, which
+// render HTML; names players give may hold any text.
+func htmlName(starID int) string {
+	return html.EscapeString(theGalaxy.Name(starID))
+}
+
+// nameLabels float the names of the selected star and its jump neighbours
+// beside them in the scene.
+type nameLabels struct {
+	group *gi3d.Group
+}
+
+var labels = &nameLabels{}
+
+// show replaces the labels with those around the star.
+func (l *nameLabels) show(sc *gi3d.Scene, starID int) {
+	if l.group == nil {
+		l.group = gi3d.AddNewGroup(sc, sc, "names")
+	}
+	l.group.DeleteChildren(true)
+	labelled := []int{starID}
+	for _, jump := range theGalaxy.JumpsByStar[starID] {
+		if next := jump.Neighbour(starID); next > -1 {
+			labelled = append(labelled, next)
+		}
+	}
+	for _, id := range labelled {
+		star := theGalaxy.Stars[id]
+		label := gi3d.AddNewText2D(sc, l.group, fmt.Sprintf("name %s", star.Key()), htmlName(id))
+		label.Pose.Scale.SetScalar(0.002)
+		label.Pose.Pos.Set(star.X+offsets.x, star.Y+offsets.y+labelHeight, star.Z+offsets.z)
+	}
+}
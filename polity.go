@@ -0,0 +1,101 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"fmt"
+	"html"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gi3d"
+	"github.com/goki/gi/gist"
+	"github.com/goki/ki/ki"
+	"virtualsoundnw.com/play/gogi3/galaxy"
+)
+
+const (
+	polityText = `<p><b>Allegiance</b> %s (%s)%s</p>`
+	// territoryAlpha is how opaque a polity's territory is; the spheres of
+	// neighbouring stars overlap into a hull around it.
+	territoryAlpha = 40
+)
+
+var (
+	// territoryModel is the sphere drawn around each star a polity holds.
+	territoryModel *gi3d.Sphere
+	politiesShown  = false
+)
+
+// showTerritory wraps a star held by a polity in a faint sphere of the
+// polity's color, if polities are turned on. Clicking it selects the star.
+func showTerritory(star *galaxy.Star, sc *gi3d.Scene) {
+	polity := theGalaxy.Allegiance(star.ID)
+	if !politiesShown || polity == nil {
+		return
+	}
+	territory := starGroup.AddNewChild(KiT_StarSolid, fmt.Sprintf("territory %s", star.Key())).(*starSolid)
+	territory.star = star
+	territory.SetMeshName(sc, territoryModel.Name())
+	territory.Defaults()
+	territory.Pose.Pos.Set(star.X+offsets.x, star.Y+offsets.y, star.Z+offsets.z)
+	territory.Mat.Color = gist.Color{R: polity.Color.R, G: polity.Color.G, B: polity.Color.B, A: territoryAlpha}
+}
+
+// addPolityControl puts the Polities check box, showing or hiding their
+// territories, on the toolbar.
+func addPolityControl(toolBar *gi.ToolBar, sceneView *gi3d.SceneView) {
+	show := gi.AddNewCheckBox(toolBar, "polities")
+	show.SetText("Polities")
+	show.SetChecked(politiesShown)
+	show.ButtonSig.Connect(sceneView.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig != int64(gi.ButtonToggled) {
+			return
+		}
+		politiesShown = show.IsChecked()
+		sc := sceneView.Scene()
+		updt := sc.UpdateStart()
+		showDetail(sc, detail)
+		sc.Init3D()
+		sc.UpdateEnd(updt)
+	})
+}
+
+// allegianceHeader names the polity holding the star, or says it is
+// non-aligned.
+func allegianceHeader(starID int) string {
+	polity := theGalaxy.Allegiance(starID)
+	if polity == nil {
+		return fmt.Sprintf(polityText, "Non-aligned", galaxy.NonAligned, "")
+	}
+	capital := ", capital"
+	if polity.Capital < 0 {
+		capital = fmt.Sprintf(", ruled from %s", html.EscapeString(polity.CapitalName))
+	} else if polity.Capital != starID {
+		capital = fmt.Sprintf(", ruled from %s", htmlName(polity.Capital))
+	}
+
+	return fmt.Sprintf(polityText, html.EscapeString(polity.Name), polity.Code, capital)
+}
+
+// byPolity selects the stars a polity holds, or the non-aligned ones.
+func byPolity(code string) selectFunc {
+	return func(g *galaxy.Galaxy) []*galaxy.Star {
+		return g.StarsOfPolity(code)
+	}
+}
+
+// filters are the fixed filters and one for each of the galaxy's polities,
+// named by its code and name like the trade codes.
+func filters() (choices map[string]selectFunc) {
+	choices = make(map[string]selectFunc, len(filter))
+	for name, choose := range filter {
+		choices[name] = choose
+	}
+	choices[galaxy.NonAligned+" Non-aligned"] = byPolity(galaxy.NonAligned)
+	for _, polity := range theGalaxy.Polities() {
+		choices[polity.Code+" "+polity.Name] = byPolity(polity.Code)
+	}
+
+	return
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"virtualsoundnw.com/play/gogi3/galaxy"
+)
+
+// reportFunc writes a report on the given worlds to w.
+type reportFunc func(g *galaxy.Galaxy, w io.Writer, worlds []*galaxy.World) error
+
+var reportFormats = map[string]reportFunc{
+	"csv":  (*galaxy.Galaxy).WriteCSV,
+	"text": (*galaxy.Galaxy).WriteText,
+	"json": writeJSON,
+}
+
+// writeJSON saves the whole galaxy, whichever worlds the report is on.
+func writeJSON(g *galaxy.Galaxy, w io.Writer, _ []*galaxy.World) error {
+	return g.WriteJSON(w)
+}
+
+// loadGalaxy reads a galaxy saved as JSON.
+func loadGalaxy(path string) (g *galaxy.Galaxy, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	g, err = galaxy.ReadJSON(f)
+	if err != nil {
+		err = fmt.Errorf("%s: %v", path, err)
+	}
+
+	return
+}
+
+// loadCampaign reads the referee's edits; there are none until the file has
+// been written.
+func loadCampaign(path string) (c *galaxy.Campaign, err error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return galaxy.NewCampaign(), nil
+	}
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	c, err = galaxy.ReadCampaign(f)
+	if err != nil {
+		err = fmt.Errorf("%s: %v", path, err)
+	}
+
+	return
+}
+
+// loadNames reads a names file written before the campaign kept names; a
+// missing file has none.
+func loadNames(path string) (names galaxy.Names, err error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return galaxy.Names{}, nil
+	}
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	names, err = galaxy.ReadNames(f)
+	if err != nil {
+		err = fmt.Errorf("%s: %v", path, err)
+	}
+
+	return
+}
+
+// writeSECFiles writes the worlds as SEC files, one per map sector. If they
+// fit in one map sector it writes path itself; otherwise each file's name
+// gains its map sector, as in traveler-report_0_1.sec. It returns the worlds
+// left out for sharing a hex with a more populous one.
+func writeSECFiles(g *galaxy.Galaxy, worlds []*galaxy.World, p galaxy.Projection, path string) (paths []string,
+	dropped []*galaxy.World, err error) {
+	sectors := p.Split(g, worlds)
+	for sector, sectorWorlds := range sectors {
+		name := path
+		if len(sectors) > 1 {
+			ext := filepath.Ext(path)
+			name = fmt.Sprintf("%s_%d_%d%s", strings.TrimSuffix(path, ext), sector.X, sector.Y, ext)
+		}
+		err = writeFile(name, func(w io.Writer) error {
+			left, err := g.WriteSEC(w, sectorWorlds, p)
+			dropped = append(dropped, left...)
+
+			return err
+		})
+		if err != nil {
+			return
+		}
+		paths = append(paths, name)
+	}
+
+	return
+}
+
+// droppedStars names the stars of worlds a SEC export left out, with the
+// hex each shared.
+func droppedStars(g *galaxy.Galaxy, dropped []*galaxy.World, p galaxy.Projection) (names []string) {
+	for _, world := range dropped {
+		star := g.Stars[world.StarID]
+		names = append(names, fmt.Sprintf("%s (%s) at hex %s", g.Name(star.ID), star.Key(), p.Hex(star)))
+	}
+
+	return
+}
+
+// writeFile creates a file and writes it, reporting any error closing it.
+func writeFile(path string, write func(w io.Writer) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
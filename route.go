@@ -0,0 +1,144 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gi3d"
+	"github.com/goki/gi/gist"
+	"github.com/goki/ki/ki"
+	"github.com/goki/mat32"
+	"virtualsoundnw.com/play/gogi3/galaxy"
+)
+
+const (
+	routeMesh = "Route"
+	routeText = `<p><b>Route</b> from %s, J%d, %s%s: %d jumps, %.2f parsecs</p>
+    <p>%s</p>`
+	noRouteText = `<p><b>Route</b> from %s, J%d, %s%s: none</p>`
+)
+
+var routeColor = gist.Color{R: 255, G: 255, B: 255, A: 255}
+
+var routeCosts = []string{"Fewest jumps", "Shortest distance"}
+
+// routePlanner finds the route from a marked star to the selected one and
+// draws it over the jump lines.
+type routePlanner struct {
+	// from is the marked star, nil until one is marked. It is found again
+	// by sector and index when the galaxy is rebuilt.
+	from  *galaxy.Star
+	opts  galaxy.RouteOptions
+	route *galaxy.Route
+	group *gi3d.Group
+}
+
+var planner = &routePlanner{opts: galaxy.RouteOptions{MaxJump: 2, Cost: galaxy.FewestJumps, FuelJumps: 1}}
+
+// addControls puts the route controls on the scene toolbar: an action to
+// mark the start, the jump rating, what to minimise and whether to refuel.
+func (p *routePlanner) addControls(toolBar *gi.ToolBar, sceneView *gi3d.SceneView) {
+	toolBar.AddAction(gi.ActOpts{Label: "Route from here"}, sceneView.This(),
+		func(recv, send ki.Ki, sig int64, data interface{}) {
+			p.from = selection.star
+			selection.updateWorldLableText(selection.currentSystem)
+		})
+
+	ratings := make([]string, 0)
+	for rating := 1; rating <= 6; rating++ {
+		ratings = append(ratings, fmt.Sprintf("J%d", rating))
+	}
+	ratingBox := gi.AddNewComboBox(toolBar, "routeRating")
+	ratingBox.ItemsFromStringList(ratings, true, len(ratings))
+	ratingBox.SetCurIndex(p.opts.MaxJump - 1)
+	ratingBox.ComboSig.Connect(sceneView.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		p.opts.MaxJump = send.(*gi.ComboBox).CurIndex + 1
+		selection.updateWorldLableText(selection.currentSystem)
+	})
+
+	costBox := gi.AddNewComboBox(toolBar, "routeCost")
+	costBox.ItemsFromStringList(routeCosts, true, len(routeCosts))
+	costBox.SetCurIndex(int(p.opts.Cost))
+	costBox.ComboSig.Connect(sceneView.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		p.opts.Cost = galaxy.RouteCost(send.(*gi.ComboBox).CurIndex)
+		selection.updateWorldLableText(selection.currentSystem)
+	})
+
+	refuel := gi.AddNewCheckBox(toolBar, "routeRefuel")
+	refuel.SetText("Refuel")
+	refuel.SetChecked(p.opts.FuelJumps > 0)
+	refuel.ButtonSig.Connect(sceneView.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig != int64(gi.ButtonToggled) {
+			return
+		}
+		p.opts.FuelJumps = 0
+		if refuel.IsChecked() {
+			p.opts.FuelJumps = 1
+		}
+		selection.updateWorldLableText(selection.currentSystem)
+	})
+}
+
+// plan finds the route from the marked star to systemID, draws it and
+// returns its description for the detail panel.
+func (p *routePlanner) plan(sc *gi3d.Scene, systemID int) (description string) {
+	p.route = nil
+	if p.from != nil {
+		from, ok := theGalaxy.Find(p.from.Sector, p.from.Index)
+		if ok && from != systemID {
+			route, err := theGalaxy.Route(from, systemID, p.opts)
+			if err == nil {
+				p.route = route
+				description = fmt.Sprintf(routeText, htmlName(from), p.opts.MaxJump, routeCosts[p.opts.Cost], p.refuelling(),
+					len(route.Jumps), route.Distance, p.stops())
+			} else {
+				description = fmt.Sprintf(noRouteText, htmlName(from), p.opts.MaxJump, routeCosts[p.opts.Cost], p.refuelling())
+			}
+		}
+	}
+	p.draw(sc)
+
+	return
+}
+
+func (p *routePlanner) refuelling() string {
+	if p.opts.FuelJumps > 0 {
+		return ", refuelling"
+	}
+
+	return ""
+}
+
+// stops lists the route's stars, with the length of each jump.
+func (p *routePlanner) stops() string {
+	stops := []string{htmlName(p.route.Stars[0])}
+	for id, jump := range p.route.Jumps {
+		stops = append(stops, fmt.Sprintf("%s (%.2f pc)", htmlName(p.route.Stars[id+1]), jump.Distance))
+	}
+
+	return strings.Join(stops, " &rarr; ")
+}
+
+// draw replaces the drawn route with the current one, if any.
+func (p *routePlanner) draw(sc *gi3d.Scene) {
+	if p.group == nil {
+		p.group = gi3d.AddNewGroup(sc, sc, "route")
+	}
+	p.group.DeleteChildren(true)
+	_ = sc.DeleteMesh(routeMesh)
+	if p.route == nil {
+		return
+	}
+	points := make([]mat32.Vec3, 0)
+	for _, starID := range p.route.Stars {
+		star := theGalaxy.Stars[starID]
+		points = append(points, mat32.Vec3{X: star.X + offsets.x, Y: star.Y + offsets.y, Z: star.Z + offsets.z})
+	}
+	mesh := gi3d.AddNewLines(sc, routeMesh, points, mat32.Vec2{X: 0.0004, Y: 0.0004}, gi3d.OpenLines)
+	solid := gi3d.AddNewSolid(sc, p.group, routeMesh, mesh.Name())
+	solid.Mat.Color = routeColor
+}
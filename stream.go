@@ -0,0 +1,88 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"time"
+
+	"github.com/goki/gi/gi3d"
+	"virtualsoundnw.com/play/gogi3/galaxy"
+)
+
+// streamInterval is how often the streamer checks where the camera is.
+const streamInterval = 250 * time.Millisecond
+
+// sectorStreamer watches the camera. As it pulls back the dimmer classes are
+// dropped from the scene, and brought back as it zooms in. When following,
+// the loaded region is also kept centred on the camera: whenever the camera
+// enters another sector the scene is rebuilt around it; sectors are
+// regenerated from their seeds as needed, so only the region and the ring of
+// sectors around it are ever held, however far the camera flies.
+type sectorStreamer struct {
+	ticker *time.Ticker
+	scene  *gi3d.Scene
+	sector galaxy.Sector
+	follow bool
+}
+
+var streamer = &sectorStreamer{}
+
+func (st *sectorStreamer) start(sc *gi3d.Scene, follow bool) {
+	st.scene = sc
+	st.follow = follow
+	st.sector = st.cameraSector()
+	st.ticker = time.NewTicker(streamInterval)
+	tickOnEventLoop(st.ticker.C, nil, st.check)
+}
+
+func (st *sectorStreamer) cameraSector() galaxy.Sector {
+	pos := st.scene.Camera.Pose.Pos
+
+	return galaxy.SectorOf(galaxy.Position{X: pos.X - offsets.x, Y: pos.Y - offsets.y, Z: pos.Z - offsets.z})
+}
+
+// cameraDistance is how far the camera is from what it is looking at.
+func (st *sectorStreamer) cameraDistance() float32 {
+	return st.scene.Camera.Pose.Pos.DistTo(st.scene.Camera.Target)
+}
+
+// check runs on the event loop at each tick, updating the detail and the
+// region for where the camera has got to.
+func (st *sectorStreamer) check(time.Time) {
+	hidden := galaxy.HiddenClasses(st.cameraDistance())
+	if hidden != detail {
+		updt := st.scene.UpdateStart()
+		showDetail(st.scene, hidden)
+		st.scene.Init3D()
+		st.scene.UpdateEnd(updt)
+	}
+	sector := st.cameraSector()
+	if !st.follow || sector == st.sector {
+		return
+	}
+	st.sector = sector
+	newRegion := region.Around(sector)
+	if newRegion != region {
+		st.reload(newRegion)
+	}
+}
+
+// reload shows the new region, keeping the selected star selected if it is
+// still loaded and otherwise selecting the star nearest the camera.
+func (st *sectorStreamer) reload(newRegion galaxy.Region) {
+	selected := selection.star
+	updt := st.scene.UpdateStart()
+	showRegion(st.scene, newRegion)
+	id, ok := theGalaxy.Find(selected.Sector, selected.Index)
+	if !ok {
+		pos := st.scene.Camera.Pose.Pos
+		id = theGalaxy.Nearest(galaxy.Position{X: pos.X - offsets.x, Y: pos.Y - offsets.y, Z: pos.Z - offsets.z})
+	}
+	if id >= 0 {
+		selection.currentSystem = id
+		selection.updateWorldLableText(id)
+	}
+	st.scene.Init3D()
+	st.scene.UpdateEnd(updt)
+}
@@ -1,21 +1,24 @@
+//go:build !headless
+// +build !headless
+
 package main
 
 import (
 	"fmt"
-	"github.com/goki/ki/kit"
-	"math"
 
 	"github.com/goki/gi/gi"
 	"github.com/goki/gi/gi3d"
 	"github.com/goki/gi/gist"
 	"github.com/goki/ki/ki"
+	"github.com/goki/ki/kit"
 	"github.com/goki/mat32"
+	"virtualsoundnw.com/play/gogi3/galaxy"
 )
 
-type selectFunc func() ([]*star)
+type selectFunc func(g *galaxy.Galaxy) []*galaxy.Star
 
 type systemSelector struct {
-	currentSystem int
+	currentSystem  int
 	scene          *gi3d.Scene
 	toolBar        *gi.ToolBar
 	jumpComboBox   *gi.ComboBox
@@ -23,26 +26,27 @@ type systemSelector struct {
 	viewPort       *gi.Viewport2D
 	sceneView      *gi3d.SceneView
 	win            *gi.Window
-	star           *star
+	star           *galaxy.Star
 	targets        []int
 	choose         selectFunc
 }
 
 var selection = systemSelector{
-	currentSystem: 0,
+	currentSystem:  0,
 	scene:          &gi3d.Scene{},
 	toolBar:        &gi.ToolBar{},
 	jumpComboBox:   &gi.ComboBox{},
 	filterComboBox: &gi.ComboBox{},
 	viewPort:       &gi.Viewport2D{},
 	sceneView:      &gi3d.SceneView{},
-	star:           &star{},
+	star:           &galaxy.Star{},
 	targets:        []int{},
-	choose:         allStars,
+	choose:         (*galaxy.Galaxy).AllStars,
 }
 
 const (
-	hdrText = `<p>Star %d </p>
+	hdrText = `<p><b>%s</b>, star %d %s</p>
+	<p><b>UWP</b> %s %s</p>
 	<p><b>StarPort</b> %s</p>
 	<p><b>Size</b> %d  </p>
 	<p><b>Atmosphere</b> %s  </p>
@@ -53,44 +57,94 @@ const (
     <p><b>Law Level</b> %d</p>
     <p><b>Tech Level</b> %d</p>
     <p><b>Tech Description</b> %s</p>`
-
-	csvTextHdr = "Star, X, Y, Z, StarPort, Size, Atmosphere, Hydro Percentage, Population, Government, Law Level,Tech Level, " +
-		"Jump1 Star and distance, Jump2 Star and distance, Jump3 Star and distance, Jump4 Star and distance, " +
-		"Jump5 Star and distance, Jump6 Star and distance, Jump7 Star and distance, Jump8 Star and distance, " +
-		"Jump9 Star and distance, Jump10 Star and distance, Jump11 Star and distance, Jump12 Star and distance, " +
-		"Jump13 Star and distance, Jump14 Star and distance, Jump15 Star and distance, Jump16 Star and distance, " +
-		"Jum17 Star and distance, Jump18 Star and distance, Jump19 Star and distance, Jump20 Star and distance, " +
-		"Jump21 Star and distance, Jump22 Star and distance, Jump23 Star and distance, Jump28 Star and distance \n"
-	csvText    = "%d, %f, %f, %f, %s, %d, %s, %d, %d, %s, %s, %d, %s\n"
+	companionsText = `<p><b>Companions</b> %s</p>`
+	noteText       = `<p><b>Note</b> %s</p>`
+	zoneText       = `<p><b>Zone</b> %s</p>`
+	importanceText = `<p><b>Importance</b> %d%s</p>`
 )
 
 var KiT_SceneView = kit.Types.AddType(&gi3d.SceneView{}, nil)
 
 var (
-	filter = map[string] selectFunc{
-		"All":             allStars,
-		"High Tech":       maxTech,
-		"Dry Worlds":      starHydroMin,
-		"Water Worlds":    starHydroMax,
-		"Largest Worlds":  maxSize,
-		"No Worlds":       minSize,
-		"Populous Worlds": maxPop,
-		"EMPTY Worlds":    minPop,
+	filter = map[string]selectFunc{
+		"All":                (*galaxy.Galaxy).AllStars,
+		"High Tech":          (*galaxy.Galaxy).MaxTech,
+		"Dry Worlds":         (*galaxy.Galaxy).StarHydroMin,
+		"Water Worlds":       (*galaxy.Galaxy).StarHydroMax,
+		"Largest Worlds":     (*galaxy.Galaxy).MaxSize,
+		"No Worlds":          (*galaxy.Galaxy).MinSize,
+		"Populous Worlds":    (*galaxy.Galaxy).MaxPop,
+		"EMPTY Worlds":       (*galaxy.Galaxy).MinPop,
+		"Ag Agricultural":    byTradeCode(galaxy.Agricultural),
+		"As Asteroid":        byTradeCode(galaxy.Asteroid),
+		"De Desert":          byTradeCode(galaxy.Desert),
+		"Hi High Population": byTradeCode(galaxy.HighPopulation),
+		"In Industrial":      byTradeCode(galaxy.Industrial),
+		"Lo Low Population":  byTradeCode(galaxy.LowPopulation),
+		"Po Poor":            byTradeCode(galaxy.Poor),
+		"Ri Rich":            byTradeCode(galaxy.Rich),
+		"Va Vacuum":          byTradeCode(galaxy.Vacuum),
+		"Wa Water World":     byTradeCode(galaxy.WaterWorld),
+		"Amber Zones":        byZone(galaxy.AmberZone),
+		"Red Zones":          byZone(galaxy.RedZone),
+		"Naval Bases":        byBase(galaxy.NavalBase),
+		"Scout Bases":        byBase(galaxy.ScoutBase),
+		"Military Bases":     byBase(galaxy.MilitaryBase),
+		"Way Stations":       byBase(galaxy.WayStation),
+		"X-boat Network":     (*galaxy.Galaxy).XBoatStars,
+		"Major Trade Routes": byTradeRoute(galaxy.MajorRoute),
 	}
 )
 
-func (s *systemSelector) updateWorldLableTextAndCamera(systemID int) (header string){
-		removeSel := s.toolBar.ChildByName("selmode", 0)
-		if removeSel != nil{
+// byTradeCode selects the stars whose worlds have a trade classification.
+func byTradeCode(code galaxy.TradeCode) selectFunc {
+	return func(g *galaxy.Galaxy) []*galaxy.Star {
+		return g.StarsWithTradeCode(code)
+	}
+}
+
+// byZone selects the stars whose worlds are in a travel zone.
+func byZone(zone galaxy.Zone) selectFunc {
+	return func(g *galaxy.Galaxy) []*galaxy.Star {
+		return g.StarsInZone(zone)
+	}
+}
+
+// byBase selects the stars whose worlds have a base.
+func byBase(base galaxy.Base) selectFunc {
+	return func(g *galaxy.Galaxy) []*galaxy.Star {
+		return g.StarsWithBase(base)
+	}
+}
+
+// byTradeRoute selects the stars on trade routes of a class.
+func byTradeRoute(route galaxy.TradeRoute) selectFunc {
+	return func(g *galaxy.Galaxy) []*galaxy.Star {
+		return g.StarsOnTradeRoute(route)
+	}
+}
+
+func (s *systemSelector) updateWorldLableTextAndCamera(systemID int) (header string) {
+	header = s.updateWorldLableText(systemID)
+	s.moveCamera()
+
+	return
+}
+
+// updateWorldLableText shows a system in the panel and toolbar and highlights
+// its jumps without moving the camera.
+func (s *systemSelector) updateWorldLableText(systemID int) (header string) {
+	removeSel := s.toolBar.ChildByName("selmode", 0)
+	if removeSel != nil {
 		s.toolBar.DeleteChild(removeSel, true)
 	}
 
-	if s.filterComboBox == nil || s.filterComboBox.Name() != "selFilter"{
+	if s.filterComboBox == nil || s.filterComboBox.Name() != "selFilter" {
 		s.filterComboBox = gi.AddNewComboBox(s.toolBar, "selFilter")
 	}
 	selections := make([]string, 0)
 	s.targets = make([]int, 0)
-	for key, _ := range filter {
+	for key, _ := range filters() {
 		selections = append(selections, key)
 	}
 
@@ -100,19 +154,18 @@ func (s *systemSelector) updateWorldLableTextAndCamera(systemID int) (header str
 	s.filterComboBox.ComboSig.ConnectOnly(s.sceneView.This(), s.filterHandler)
 
 	if s.jumpComboBox == nil || s.jumpComboBox.Name() != "selJump" {
-	s.jumpComboBox = gi.AddNewComboBox(s.toolBar, "selJump")
+		s.jumpComboBox = gi.AddNewComboBox(s.toolBar, "selJump")
 	}
 	selections = make([]string, 0)
 	s.targets = make([]int, 0)
-	for id, jump := range jumpsByStar[systemID] {
-		nextStar := -1
-		if systemID == jump.s1ID {
-			nextStar = jump.s2ID
-		} else {
-			nextStar = jump.s1ID
-		}
+	for id, jump := range theGalaxy.JumpsByStar[systemID] {
+		nextStar := jump.Neighbour(systemID)
 		if nextStar != -1 {
-			selections = append(selections, fmt.Sprintf("Jump #%d to star %d", id+1, nextStar))
+			label := fmt.Sprintf("Jump #%d to %s", id+1, theGalaxy.Name(nextStar))
+			if route := theGalaxy.TradeRoute(jump); route != galaxy.NoRoute {
+				label += fmt.Sprintf(" (%s route)", route)
+			}
+			selections = append(selections, label)
 			s.targets = append(s.targets, nextStar)
 		}
 	}
@@ -123,47 +176,75 @@ func (s *systemSelector) updateWorldLableTextAndCamera(systemID int) (header str
 
 	s.scene.SetActiveStateUpdt(true)
 
-	s.star = stars[systemID]
-	header = worldFromStar(systemID).worldHeader
+	s.star = theGalaxy.Stars[systemID]
+	header = planets.header(systemID) + trade.header(systemID) + planner.plan(s.scene, systemID)
 	workingWorld.SystemDetails.Redrawable = true
 	workingWorld.worldHeader = header
 	workingWorld.SystemDetails.CurBgColor = gist.Color{R: 0, G: 0, B: 0, A: 255}
 	workingWorld.SystemDetails.SetText(header)
-	s.scene.Camera.Pose.Pos.Set(float32(stars[systemID].x) + offsets.x, float32(stars[systemID].y) + offsets.y, float32(stars[systemID].z+offsets.z) + 0.1)
-	s.scene.Camera.LookAt(mat32.Vec3{
-		X: float32(stars[systemID].x) + offsets.x,
-		Y: float32(stars[systemID].y) + offsets.y,
-		Z: float32(stars[systemID].z) + offsets.z,
-	}, mat32.Vec3{
-		X: 0,
-		Y: .1,
-		Z: 0,
-	})
 
-	for id, l := range lines {
-		thicker := float32(1.0)
-		if l.jumpInfo.s1ID == systemID ||
-			l.jumpInfo.s2ID == systemID {
-			l.jumpInfo.activeColor.R = l.jumpInfo.color.R + eighth
-			l.jumpInfo.color.G = l.jumpInfo.color.G + eighth
-			l.jumpInfo.color.B = l.jumpInfo.color.B + eighth
-			thicker = float32(10.0)
+	for _, l := range lines {
+		selected := l.jumpInfo.S1ID == systemID || l.jumpInfo.S2ID == systemID
+		if l.highlight(selected) {
+			_ = s.scene.InitMesh(l.lines.Name())
 		}
-		thickness := float32(0.00005)
-		if l.jumpInfo.color.A < math.MaxUint8-55 {
-			thickness = 0.00010 * thicker
-		} else if l.jumpInfo.color.A < math.MaxUint8-47 {
-			thickness = 0.00012 * thicker
-		} else if l.jumpInfo.color.A < math.MaxUint8-39 {
-			thickness = 0.00015 * thicker
-		}
-		lines[id].lines.Width = mat32.Vec2{X: thickness, Y: thickness}
 	}
+	labels.show(s.scene, systemID)
+	showBaseIcons(systemID)
+	editor.show(systemID)
 	s.scene.SetActiveStateUpdt(false)
 
 	return
 }
 
+// step selects the star by places along the filter's stars from the
+// selected one, wrapping round. From a star the filter leaves out it starts
+// at the first, and with no stars to choose it does nothing.
+func (s *systemSelector) step(by int) {
+	chosen := s.choose(theGalaxy)
+	if len(chosen) == 0 {
+		return
+	}
+	match := -1
+	for id, next := range chosen {
+		if next.ID == s.currentSystem {
+			match = id
+			break
+		}
+	}
+	if match < 0 {
+		match = 0
+	} else {
+		match = (match + by + len(chosen)) % len(chosen)
+	}
+	s.currentSystem = chosen[match].ID
+	s.updateWorldLableTextAndCamera(s.currentSystem)
+}
+
+// moveCamera flies the camera to just in front of the selected star.
+func (s *systemSelector) moveCamera() {
+	camera.flyTo(mat32.Vec3{
+		X: s.star.X + offsets.x,
+		Y: s.star.Y + offsets.y,
+		Z: s.star.Z + offsets.z + 0.1,
+	}, mat32.Vec3{
+		X: s.star.X + offsets.x,
+		Y: s.star.Y + offsets.y,
+		Z: s.star.Z + offsets.z,
+	})
+}
+
+// selectStar selects a star clicked in the scene.
+func (s *systemSelector) selectStar(star *galaxy.Star) {
+	id, ok := theGalaxy.Find(star.Sector, star.Index)
+	if !ok {
+		return
+	}
+	s.currentSystem = id
+	s.updateWorldLableTextAndCamera(id)
+	s.sceneView.UpdateSig()
+}
+
 func (s *systemSelector) handler(recv, send ki.Ki, sig int64, data interface{}) {
 	svv := recv.Embed(KiT_SceneView).(*gi3d.SceneView)
 	cbb := send.(*gi.ComboBox)
@@ -179,13 +260,18 @@ func (s *systemSelector) filterHandler(recv, send ki.Ki, sig int64, data interfa
 	svv := recv.Embed(KiT_SceneView).(*gi3d.SceneView)
 	cbb := send.(*gi.ComboBox)
 	//scc := svv.Scene()
-	if cbb.CurIndex < len(filter) {
+	choices := filters()
+	if cbb.CurIndex < len(choices) {
 		sel := cbb.CurVal.(string)
-		if filter[sel] != nil {
-			s.choose = filter[sel]
+		if choices[sel] != nil {
+			s.choose = choices[sel]
 		}
-		s.currentSystem = s.choose()[0].id
+		chosen := s.choose(theGalaxy)
+		if len(chosen) == 0 {
+			return
+		}
+		s.currentSystem = chosen[0].ID
 		s.updateWorldLableTextAndCamera(s.currentSystem)
 		svv.UpdateSig()
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,59 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gi3d"
+	"github.com/goki/ki/ki"
+	"virtualsoundnw.com/play/gogi3/galaxy"
+)
+
+const (
+	systemText = `<p><b>System</b> of %s, star %d %s, habitable zone at %.2f AU</p>
+    %s`
+	bodyText = `<p>%s</p>`
+)
+
+// systemView shows the selected star's planets, belts and satellites in the
+// detail panel in place of its mainworld while System is ticked.
+type systemView struct {
+	on bool
+}
+
+var planets = &systemView{}
+
+// addControl puts the System check box on the toolbar.
+func (v *systemView) addControl(toolBar *gi.ToolBar, sceneView *gi3d.SceneView) {
+	show := gi.AddNewCheckBox(toolBar, "system")
+	show.SetText("System")
+	show.ButtonSig.Connect(sceneView.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			v.on = show.IsChecked()
+			selection.updateWorldLableText(selection.currentSystem)
+		}
+	})
+}
+
+// header describes the star's system, or its world when the view is off.
+func (v *systemView) header(starID int) string {
+	if !v.on {
+		return worldHeader(theGalaxy.World(starID))
+	}
+
+	return systemHeader(theGalaxy.Stars[starID], theGalaxy.System(starID))
+}
+
+func systemHeader(star *galaxy.Star, system *galaxy.System) string {
+	bodies := make([]string, 0)
+	for _, line := range system.Describe() {
+		bodies = append(bodies, fmt.Sprintf(bodyText, line))
+	}
+
+	return fmt.Sprintf(systemText, htmlName(star.ID), star.ID, star.Spectral(), system.HabitableAU,
+		strings.Join(bodies, "\n    "))
+}
@@ -0,0 +1,125 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/goki/gi/gi"
+	"github.com/goki/gi/gi3d"
+	"github.com/goki/ki/ki"
+	"virtualsoundnw.com/play/gogi3/galaxy"
+)
+
+const (
+	marketText = `<p><b>WTN</b> %.1f</p>
+    <p><b>Market</b></p>%s`
+	cargoText      = `<p>%s: %d tons at Cr%d (DM %+d)</p>`
+	trafficText    = `<p><b>Traffic</b></p>%s`
+	jumpTraffic    = `<p>To %s, J%d%s: %d tons in %d lots at Cr%d, passengers %s, %d mail; pays Cr%d%s</p>`
+	tradeRouteText = `, %s route (BTN %.1f)`
+	bestCargo      = `, best cargo %s at Cr%d a ton`
+	dealsText      = `<p><b>Carrying from</b> %s</p>%s`
+	dealText       = `<p>%s: buy at Cr%d, sell at Cr%d, Cr%d a ton</p>`
+	noSaleText     = `<p>Nothing for sale</p>`
+)
+
+// shownDeals is how many of the speculative deals from the marked star are
+// shown.
+const shownDeals = 5
+
+// tradeView adds the selected world's market, the traffic waiting for each
+// of its jumps and what pays to carry from the star marked with "Route from
+// here" to the detail panel while Trade is ticked.
+type tradeView struct {
+	on bool
+}
+
+var trade = &tradeView{}
+
+// addControl puts the Trade check box on the toolbar.
+func (v *tradeView) addControl(toolBar *gi.ToolBar, sceneView *gi3d.SceneView) {
+	show := gi.AddNewCheckBox(toolBar, "trade")
+	show.SetText("Trade")
+	show.ButtonSig.Connect(sceneView.This(), func(recv, send ki.Ki, sig int64, data interface{}) {
+		if sig == int64(gi.ButtonToggled) {
+			v.on = show.IsChecked()
+			selection.updateWorldLableText(selection.currentSystem)
+		}
+	})
+}
+
+// header describes the star's trade, or nothing when the view is off.
+func (v *tradeView) header(starID int) string {
+	if !v.on {
+		return ""
+	}
+	header := fmt.Sprintf(marketText, theGalaxy.World(starID).WTN(), marketLines(theGalaxy.Market(starID))) +
+		fmt.Sprintf(trafficText, trafficLines(starID))
+	if planner.from != nil {
+		from, ok := theGalaxy.Find(planner.from.Sector, planner.from.Index)
+		if ok && from != starID {
+			header += fmt.Sprintf(dealsText, htmlName(from), dealLines(theGalaxy.Speculation(from, starID)))
+		}
+	}
+
+	return header
+}
+
+func marketLines(market []galaxy.Cargo) string {
+	if len(market) == 0 {
+		return noSaleText
+	}
+	lines := make([]string, 0)
+	for _, cargo := range market {
+		lines = append(lines, fmt.Sprintf(cargoText, html.EscapeString(cargo.Good.Name), cargo.Tons, cargo.Price,
+			cargo.PurchaseDM))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// trafficLines sums up the traffic for each jump, with the most profitable
+// cargo to carry along it.
+func trafficLines(starID int) string {
+	lines := make([]string, 0)
+	for _, jump := range theGalaxy.JumpsByStar[starID] {
+		traffic := theGalaxy.Traffic(starID, jump)
+		passengers := make([]string, 0)
+		for id, passage := range galaxy.Passages {
+			passengers = append(passengers, fmt.Sprintf("%d %s", traffic.Passengers[id], strings.ToLower(passage.Name)))
+		}
+		best := ""
+		if deals := theGalaxy.Speculation(starID, traffic.To); len(deals) > 0 && deals[0].Profit > 0 {
+			best = fmt.Sprintf(bestCargo, html.EscapeString(deals[0].Good.Name), deals[0].Profit)
+		}
+		route := ""
+		if class := theGalaxy.TradeRoute(jump); class != galaxy.NoRoute {
+			route = fmt.Sprintf(tradeRouteText, class, theGalaxy.TradeVolume(jump))
+		}
+		lines = append(lines, fmt.Sprintf(jumpTraffic, htmlName(traffic.To), traffic.Parsecs, route,
+			traffic.FreightTons(), len(traffic.Freight), traffic.FreightRate, strings.Join(passengers, ", "),
+			traffic.MailContainers, traffic.Revenue(), best))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func dealLines(speculation []galaxy.Deal) string {
+	if len(speculation) == 0 {
+		return noSaleText
+	}
+	if len(speculation) > shownDeals {
+		speculation = speculation[:shownDeals]
+	}
+	lines := make([]string, 0)
+	for _, deal := range speculation {
+		lines = append(lines, fmt.Sprintf(dealText, html.EscapeString(deal.Good.Name), deal.Price, deal.SalePrice,
+			deal.Profit))
+	}
+
+	return strings.Join(lines, "\n")
+}